@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"math/big"
 	"strings"
 
 	"github.com/sachinaralapura/shoebill/token"
@@ -57,9 +58,10 @@ func (p *Program) String() string {
 statement interface
 */
 type LetStatement struct {
-	Token token.Token // 'let' token
-	Name  *Identifier // identifier
-	Value Expression
+	Token   token.Token // 'let' token
+	Name    *Identifier // identifier, nil when Pattern is set
+	Pattern Expression  // *ArrayPattern or *HashPattern of a destructuring let
+	Value   Expression
 }
 
 func (ls *LetStatement) statementNode()       {}
@@ -68,7 +70,11 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString("=")
 	if ls.Value != nil {
 		out.WriteString(ls.Value.String())
@@ -77,6 +83,111 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+// PatternElement binds one value of a destructuring pattern
+type PatternElement struct {
+	Key     Expression  // hash patterns only, the Identifier or StringLiteral that is looked up
+	Name    *Identifier // binding
+	Default Expression  // used when the value is missing, may be nil
+}
+
+func (pe *PatternElement) String() string {
+	var out bytes.Buffer
+	if pe.Key != nil && pe.Key.String() != pe.Name.String() {
+		out.WriteString(pe.Key.String() + ": ")
+	}
+	out.WriteString(pe.Name.String())
+	if pe.Default != nil {
+		out.WriteString(" = " + pe.Default.String())
+	}
+	return out.String()
+}
+
+/*
+Array destructuring pattern, implements expression interface
+
+	let [a, b = 2, ...tail] = arr;
+*/
+type ArrayPattern struct {
+	Token    token.Token // '[' token
+	Elements []*PatternElement
+	Rest     *Identifier // may be nil
+}
+
+func (ap *ArrayPattern) expressionNode()      {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, e := range ap.Elements {
+		elements = append(elements, e.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+/*
+Hash destructuring pattern, implements expression interface
+
+	let {name, age: years = 0} = person;
+*/
+type HashPattern struct {
+	Token    token.Token // '{' token
+	Elements []*PatternElement
+}
+
+func (hp *HashPattern) expressionNode()      {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	elements := []string{}
+	for _, e := range hp.Elements {
+		elements = append(elements, e.String())
+	}
+	return "{" + strings.Join(elements, ", ") + "}"
+}
+
+// PatternNames returns the names bound by an *ArrayPattern or *HashPattern
+func PatternNames(pattern Expression) []string {
+	var names []string
+	switch pattern := pattern.(type) {
+	case *ArrayPattern:
+		for _, e := range pattern.Elements {
+			names = append(names, e.Name.Value)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest.Value)
+		}
+	case *HashPattern:
+		for _, e := range pattern.Elements {
+			names = append(names, e.Name.Value)
+		}
+	}
+	return names
+}
+
+// --------------------- Const statement --------------------
+// statement interface
+type ConstStatement struct {
+	Token token.Token // 'const' token
+	Name  *Identifier // identifier
+	Value Expression
+}
+
+func (cs *ConstStatement) statementNode()       {}
+func (cs *ConstStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ConstStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cs.TokenLiteral() + " ")
+	out.WriteString(cs.Name.String())
+	out.WriteString("=")
+	if cs.Value != nil {
+		out.WriteString(cs.Value.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
 // --------------------- Return statement --------------------
 // statement interface
 type ReturnStatement struct {
@@ -96,6 +207,197 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+// --------------------- While statement --------------------
+// statement interface
+type WhileStatement struct {
+	Token     token.Token // 'while' token
+	Label     *Identifier // optional
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+	writeLabel(&out, ws.Label)
+	out.WriteString("while(")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(")")
+	out.WriteString(ws.Body.String())
+	return out.String()
+}
+
+// --------------------- For statement --------------------
+// statement interface
+//
+//	for (<init>; <condition>; <update>) { <statements> }
+type ForStatement struct {
+	Token     token.Token // 'for' token
+	Label     *Identifier // optional
+	Init      Statement   // optional
+	Condition Expression  // optional
+	Update    Statement   // optional
+	Body      *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+	writeLabel(&out, fs.Label)
+	out.WriteString("for(")
+	if fs.Init != nil {
+		out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+	}
+	out.WriteString(";")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString(";")
+	if fs.Update != nil {
+		out.WriteString(strings.TrimSuffix(fs.Update.String(), ";"))
+	}
+	out.WriteString(")")
+	out.WriteString(fs.Body.String())
+	return out.String()
+}
+
+// --------------------- For In statement --------------------
+// statement interface
+//
+//	for (<identifier> in <expression>) { <statements> }
+type ForInStatement struct {
+	Token    token.Token // 'for' token
+	Label    *Identifier // optional
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) statementNode()       {}
+func (fs *ForInStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForInStatement) String() string {
+	var out bytes.Buffer
+	writeLabel(&out, fs.Label)
+	out.WriteString("for(")
+	out.WriteString(fs.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(")")
+	out.WriteString(fs.Body.String())
+	return out.String()
+}
+
+// writeLabel writes the optional label of a loop statement
+func writeLabel(out *bytes.Buffer, label *Identifier) {
+	if label != nil {
+		out.WriteString(label.String() + ": ")
+	}
+}
+
+// --------------------- Break statement --------------------
+// statement interface
+type BreakStatement struct {
+	Token token.Token // 'break' token
+	Label *Identifier // optional
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) String() string {
+	if bs.Label != nil {
+		return bs.TokenLiteral() + " " + bs.Label.String() + ";"
+	}
+	return bs.TokenLiteral() + ";"
+}
+
+// --------------------- Continue statement --------------------
+// statement interface
+type ContinueStatement struct {
+	Token token.Token // 'continue' token
+	Label *Identifier // optional
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) String() string {
+	if cs.Label != nil {
+		return cs.TokenLiteral() + " " + cs.Label.String() + ";"
+	}
+	return cs.TokenLiteral() + ";"
+}
+
+// --------------------- Throw statement --------------------
+// statement interface
+type ThrowStatement struct {
+	Token token.Token // 'throw' token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
+// --------------------- Import statement --------------------
+// statement interface
+//
+//	import "<path>" as <identifier>;
+type ImportStatement struct {
+	Token token.Token // 'import' token
+	Path  *StringLiteral
+	Alias *Identifier
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) String() string {
+	return fmt.Sprintf("%s %q as %s;", is.TokenLiteral(), is.Path.Value, is.Alias.String())
+}
+
+// --------------------- Export statement --------------------
+// statement interface, exports the bindings of a let, const or class statement
+type ExportStatement struct {
+	Token     token.Token // 'export' token
+	Statement Statement
+}
+
+func (es *ExportStatement) statementNode()       {}
+func (es *ExportStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
+
+// --------------------- Class statement --------------------
+// statement interface
+//
+//	class <identifier> { let <field> = <expression>; fn <method>(<params>) { <statements> } }
+type ClassStatement struct {
+	Token   token.Token // 'class' token
+	Name    *Identifier
+	Fields  []*LetStatement
+	Methods []*FunctionLiteral
+}
+
+func (cs *ClassStatement) statementNode()       {}
+func (cs *ClassStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ClassStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("class ")
+	out.WriteString(cs.Name.String())
+	out.WriteString("{")
+	for _, field := range cs.Fields {
+		out.WriteString(field.String())
+	}
+	for _, method := range cs.Methods {
+		out.WriteString(method.String())
+	}
+	out.WriteString("}")
+	return out.String()
+}
+
 // --------------------- Expression statement --------------------
 // statement interface
 type ExpressionStatement struct {
@@ -166,6 +468,36 @@ func (i *IntegerLiteral) expressionNode()      {}
 func (i *IntegerLiteral) TokenLiteral() string { return i.Token.Literal }
 func (i *IntegerLiteral) String() string       { return fmt.Sprint(i.Value) }
 
+/*
+Implements expression interface
+
+	BigInt Literal expression, an integer literal too large for int64 ex :
+	>> 123456789012345678901234567890;
+*/
+type BigIntLiteral struct {
+	Token token.Token
+	Value *big.Int
+}
+
+func (bi *BigIntLiteral) expressionNode()      {}
+func (bi *BigIntLiteral) TokenLiteral() string { return bi.Token.Literal }
+func (bi *BigIntLiteral) String() string       { return bi.Value.String() }
+
+/*
+Implements expression interface
+
+	Float Literal expression ex :
+	>> 3.14;
+*/
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 // implements expression interface
 type StringLiteral struct {
 	Token token.Token
@@ -189,6 +521,16 @@ func (be *BoolenExpression) expressionNode()      {}
 func (be *BoolenExpression) TokenLiteral() string { return be.Token.Literal }
 func (be *BoolenExpression) String() string       { return be.Token.Literal }
 
+// Null Literal
+// implements Expression interface
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (nl *NullLiteral) expressionNode()      {}
+func (nl *NullLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NullLiteral) String() string       { return nl.Token.Literal }
+
 // Array Expression
 // implement Expression interface
 type ArrayExpression struct {
@@ -212,11 +554,12 @@ func (ae *ArrayExpression) String() string {
 }
 
 // Index Expression
-// <expression>[<expression>]
+// <expression>[<expression>] or the optional <expression>?[<expression>]
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Optional bool // ?[ yields null when Left is null
 }
 
 func (ie *IndexExpression) expressionNode()      {}
@@ -224,17 +567,82 @@ func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
 func (ie *IndexExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString("[")
 	out.WriteString(ie.Index.String())
 	out.WriteString("]")
 	return out.String()
 }
 
+/*
+Range expression, implements Expression interface
+
+	1..10
+	0..<n step 2
+*/
+type RangeExpression struct {
+	Token     token.Token // '..' or '..<' token
+	Start     Expression
+	End       Expression
+	Step      Expression // may be nil
+	Exclusive bool       // ..< leaves out End
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(re.Start.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.End.String())
+	if re.Step != nil {
+		out.WriteString(" step " + re.Step.String())
+	}
+	out.WriteString(")")
+	return out.String()
+}
+
+// Slice Expression
+// <expression>[<start>:<end>:<step>], each part may be omitted
+type SliceExpression struct {
+	Token    token.Token // '[' or '?[' token
+	Left     Expression
+	Start    Expression // may be nil
+	End      Expression // may be nil
+	Step     Expression // may be nil
+	Optional bool       // ?[ yields null when Left is null
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(se.Left.String())
+	if se.Optional {
+		out.WriteString("?")
+	}
+	out.WriteString("[")
+	for i, part := range []Expression{se.Start, se.End, se.Step} {
+		if i > 0 && (i < 2 || part != nil) {
+			out.WriteString(":")
+		}
+		if part != nil {
+			out.WriteString(part.String())
+		}
+	}
+	out.WriteString("]")
+	return out.String()
+}
+
 // Hash Literal
 // {<expression>:<expression> , <expression> : <expression>}
 type HashLiteral struct {
 	Token token.Token
 	Pairs map[Expression]Expression
+	Order []Expression // keys of Pairs and *SpreadExpression entries in source order
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -242,8 +650,12 @@ func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range hl.Order {
+		if spread, ok := key.(*SpreadExpression); ok {
+			pairs = append(pairs, spread.String())
+			continue
+		}
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairs, ", "))
@@ -251,6 +663,87 @@ func (hl *HashLiteral) String() string {
 	return out.String()
 }
 
+/*
+Spread expression, expands an iterable into an array literal or call arguments
+and a hash into a hash literal, implements Expression interface
+
+	[...a, ...b]
+	{...base, "k": v}
+	f(...args)
+*/
+type SpreadExpression struct {
+	Token token.Token // '...' token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string       { return "..." + se.Value.String() }
+
+// Dot Expression
+// <expression>.<identifier> or the optional <expression>?.<identifier>
+type DotExpression struct {
+	Token    token.Token // '.' or '?.' token
+	Left     Expression
+	Property *Identifier
+	Optional bool // ?. yields null when Left is null
+}
+
+func (de *DotExpression) expressionNode()      {}
+func (de *DotExpression) TokenLiteral() string { return de.Token.Literal }
+func (de *DotExpression) String() string {
+	return de.Left.String() + de.Token.Literal + de.Property.String()
+}
+
+// Assign Expression
+// <target> = <expression> or a compound form such as <target> += <expression>
+type AssignExpression struct {
+	Token    token.Token // '=' or compound assignment token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(ae.Operator)
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+	return out.String()
+}
+
+// IsOptionalChain reports whether exp is a chain of property accesses, indexes
+// and calls that contains a ?. or ?[ link
+func IsOptionalChain(exp Expression) bool {
+	for {
+		switch e := exp.(type) {
+		case *DotExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Left
+		case *IndexExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Left
+		case *SliceExpression:
+			if e.Optional {
+				return true
+			}
+			exp = e.Left
+		case *CallExpression:
+			exp = e.Function
+		default:
+			return false
+		}
+	}
+}
+
 /*
 IF ELSE Expression
 Implements Expression interface
@@ -278,13 +771,89 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+/*
+Match Expression
+Implements Expression interface
+
+	match (<expression>) { <pattern>, <pattern> => <arm>, _ => <arm> }
+*/
+type MatchExpression struct {
+	Token   token.Token // 'match' token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+// MatchArm is taken when the subject equals one of its patterns,
+// the identifier _ matches any value
+type MatchArm struct {
+	Patterns []Expression
+	Body     *BlockStatement
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+	arms := []string{}
+	for _, arm := range me.Arms {
+		patterns := []string{}
+		for _, pattern := range arm.Patterns {
+			patterns = append(patterns, pattern.String())
+		}
+		arms = append(arms, strings.Join(patterns, ",")+"=>"+arm.Body.String())
+	}
+	out.WriteString("match(")
+	out.WriteString(me.Subject.String())
+	out.WriteString("){")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+/*
+Try Expression
+Implements Expression interface
+
+	try { <statements> } catch (<identifier>) { <statements> } finally { <statements> }
+*/
+type TryExpression struct {
+	Token      token.Token // 'try' token
+	Block      *BlockStatement
+	CatchParam *Identifier     // optional
+	Catch      *BlockStatement // optional when Finally is set
+	Finally    *BlockStatement // optional
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try")
+	out.WriteString(te.Block.String())
+	if te.Catch != nil {
+		out.WriteString("catch")
+		if te.CatchParam != nil {
+			out.WriteString("(" + te.CatchParam.String() + ")")
+		}
+		out.WriteString(te.Catch.String())
+	}
+	if te.Finally != nil {
+		out.WriteString("finally")
+		out.WriteString(te.Finally.String())
+	}
+	return out.String()
+}
+
 /*
 Function Literal
 Implements Expression
 */
 type FunctionLiteral struct {
 	Token      token.Token
+	Name       string // set for class methods
 	Parameters []*Identifier
+	Defaults   []Expression // default value of each parameter, nil where there is none
+	Rest       *Identifier  // collects extra arguments as in fn(a, ...rest), may be nil
 	Body       *BlockStatement
 }
 
@@ -292,11 +861,11 @@ func (fe *FunctionLiteral) TokenLiteral() string { return fe.Token.Literal }
 func (fe *FunctionLiteral) expressionNode()      {}
 func (fe *FunctionLiteral) String() string {
 	var out bytes.Buffer
-	params := []string{}
-	for _, p := range fe.Parameters {
-		params = append(params, p.String())
-	}
+	params := FormatParameters(fe.Parameters, fe.Defaults, fe.Rest)
 	out.WriteString(fe.TokenLiteral())
+	if fe.Name != "" {
+		out.WriteString(" " + fe.Name)
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ","))
 	out.WriteString(")")
@@ -305,6 +874,37 @@ func (fe *FunctionLiteral) String() string {
 	return out.String()
 }
 
+// FormatParameters renders a parameter list with its defaults and rest parameter
+func FormatParameters(parameters []*Identifier, defaults []Expression, rest *Identifier) []string {
+	params := []string{}
+	for i, p := range parameters {
+		if i < len(defaults) && defaults[i] != nil {
+			params = append(params, p.String()+" = "+defaults[i].String())
+			continue
+		}
+		params = append(params, p.String())
+	}
+	if rest != nil {
+		params = append(params, "..."+rest.String())
+	}
+	return params
+}
+
+/*
+keyword argument of a call, implements Expression interface
+
+	add(1, b: 2)
+*/
+type KeywordArgument struct {
+	Token token.Token // the name token
+	Name  *Identifier
+	Value Expression
+}
+
+func (ka *KeywordArgument) expressionNode()      {}
+func (ka *KeywordArgument) TokenLiteral() string { return ka.Token.Literal }
+func (ka *KeywordArgument) String() string       { return ka.Name.String() + ": " + ka.Value.String() }
+
 /*
 function call expression
 implements Expression interface
@@ -0,0 +1,114 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/sachinaralapura/shoebill/object"
+)
+
+// maxBigIntBits bounds the size of results of << and ** so a huge shift count or exponent
+// is reported as an error instead of exhausting memory
+const maxBigIntBits = 1 << 24
+
+// evalBigIntInfixExpression handles integer operands when at least one is a BigInt
+// or when int64 arithmetic on them overflowed
+func evalBigIntInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := toBigInt(left)
+	rightValue := toBigInt(right)
+	switch operator {
+	case "+":
+		return normalizeBigInt(new(big.Int).Add(leftValue, rightValue))
+	case "-":
+		return normalizeBigInt(new(big.Int).Sub(leftValue, rightValue))
+	case "*":
+		return normalizeBigInt(new(big.Int).Mul(leftValue, rightValue))
+	case "/":
+		if rightValue.Sign() == 0 {
+			return newErrorObject("division by zero: %s / 0", leftValue)
+		}
+		return normalizeBigInt(new(big.Int).Quo(leftValue, rightValue))
+	case "%":
+		if rightValue.Sign() == 0 {
+			return newErrorObject("division by zero: %s %% 0", leftValue)
+		}
+		return normalizeBigInt(new(big.Int).Rem(leftValue, rightValue))
+	case "&":
+		return normalizeBigInt(new(big.Int).And(leftValue, rightValue))
+	case "|":
+		return normalizeBigInt(new(big.Int).Or(leftValue, rightValue))
+	case "^":
+		return normalizeBigInt(new(big.Int).Xor(leftValue, rightValue))
+	case "<<", ">>":
+		if rightValue.Sign() < 0 {
+			return newErrorObject("negative shift count: %s %s %s", leftValue, operator, rightValue)
+		}
+		if !rightValue.IsInt64() {
+			return newErrorObject("shift count too large: %s %s %s", leftValue, operator, rightValue)
+		}
+		if operator == "<<" {
+			if leftValue.Sign() != 0 && rightValue.Int64() > maxBigIntBits-int64(leftValue.BitLen()) {
+				return newErrorObject("shift count too large: %s << %s", leftValue, rightValue)
+			}
+			return normalizeBigInt(new(big.Int).Lsh(leftValue, uint(rightValue.Int64())))
+		}
+		return normalizeBigInt(new(big.Int).Rsh(leftValue, uint(rightValue.Int64())))
+	case "**":
+		if rightValue.Sign() < 0 {
+			if leftValue.Sign() == 0 {
+				return newErrorObject("division by zero: %s ** %s", leftValue, rightValue)
+			}
+			// a negative exponent gives a fraction, as in 2 ** -1 == 0.5
+			return &object.Float{Value: math.Pow(toFloat(left), toFloat(right))}
+		}
+		// 0, 1 and -1 stay small for any exponent, other bases grow by about BitLen bits per step
+		if leftValue.CmpAbs(big.NewInt(1)) > 0 &&
+			(!rightValue.IsInt64() || rightValue.Int64() > maxBigIntBits/int64(leftValue.BitLen())) {
+			return newErrorObject("exponent too large: %s ** %s", leftValue, rightValue)
+		}
+		return normalizeBigInt(new(big.Int).Exp(leftValue, rightValue, nil))
+	case "<":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) >= 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) != 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) == 0)
+	default:
+		return newTypeErrorObject("unknown operator : %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// normalizeBigInt returns an Integer when value fits in int64, so a BigInt never holds a small value
+func normalizeBigInt(value *big.Int) object.Object {
+	if value.IsInt64() {
+		return &object.Integer{Value: value.Int64()}
+	}
+	return &object.BigInt{Value: value}
+}
+
+// toBigInt converts an object accepted by isInteger to a big.Int that is safe to read but not to modify
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	default:
+		return new(big.Int)
+	}
+}
+
+func isInteger(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.BigInt:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,6 +1,12 @@
 package evaluator
 
 import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/sachinaralapura/shoebill/object"
 )
 
@@ -10,9 +16,11 @@ func lenBuildIn(args ...object.Object) object.Object {
 	}
 	switch arg := args[0].(type) {
 	case *object.String:
-		return &object.Integer{Value: int64(len(arg.Value))}
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 	case *object.Array:
 		return &object.Integer{Value: int64(len(arg.Elements))}
+	case *object.Range:
+		return &object.Integer{Value: arg.Len()}
 	default:
 		return newErrorObject("argument to `len` not supported, got %s", args[0].Type())
 	}
@@ -85,6 +93,51 @@ func pushBuildIn(args ...object.Object) object.Object {
 	return &object.Array{Elements: newElements}
 }
 
+func floatBuildIn(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newErrorObject("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	switch arg := args[0].(type) {
+	case *object.Float:
+		return arg
+	case *object.Integer, *object.BigInt:
+		return &object.Float{Value: toFloat(arg)}
+	case *object.String:
+		value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+		if err != nil {
+			return newErrorObject("could not convert %q to FLOAT", arg.Value)
+		}
+		return &object.Float{Value: value}
+	default:
+		return newErrorObject("argument to `float` not supported, got %s", args[0].Type())
+	}
+}
+
+// intBuildIn truncates floats towards zero and reads strings as decimal
+func intBuildIn(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newErrorObject("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.BigInt:
+		return arg
+	case *object.Float:
+		if math.IsNaN(arg.Value) || math.IsInf(arg.Value, 0) {
+			return newErrorObject("could not convert %s to INTEGER", arg.Inspect())
+		}
+		value, _ := big.NewFloat(arg.Value).Int(nil)
+		return normalizeBigInt(value)
+	case *object.String:
+		value, ok := new(big.Int).SetString(strings.TrimSpace(arg.Value), 10)
+		if !ok {
+			return newErrorObject("could not convert %q to INTEGER", arg.Value)
+		}
+		return normalizeBigInt(value)
+	default:
+		return newErrorObject("argument to `int` not supported, got %s", args[0].Type())
+	}
+}
+
 func printBuildIn(args ...object.Object) object.Object {
 	return NULL
 }
@@ -96,4 +149,6 @@ var BuildIns = map[string]*object.BuildIn{
 	"last":  {Value: lastBuildIn},
 	"rest":  {Value: restBuildIn},
 	"push":  {Value: pushBuildIn},
+	"float": {Value: floatBuildIn},
+	"int":   {Value: intBuildIn},
 }
@@ -2,6 +2,9 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"strings"
 
 	"github.com/sachinaralapura/shoebill/ast"
 	"github.com/sachinaralapura/shoebill/object"
@@ -13,18 +16,74 @@ var (
 	FALSE = &object.Boolean{Value: false}
 )
 
+// CheckedArithmetic makes integer arithmetic report int64 overflow as an error instead of promoting to BigInt
+var CheckedArithmetic = false
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node.Statements, env)
 
 	case *ast.LetStatement:
+		if node.Pattern != nil {
+			val := Eval(node.Value, env)
+			if isError(val) {
+				return val
+			}
+			return evalDestructuring(node.Pattern, val, env)
+		}
+		if env.IsConstant(node.Name.Value) {
+			return newErrorObject("cannot redeclare constant: %s", node.Name.Value)
+		}
 		val := Eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
+		nameFunction(node.Value, val, node.Name.Value)
 		env.Set(node.Name.Value, val)
 
+	case *ast.ConstStatement:
+		if env.IsConstant(node.Name.Value) {
+			return newErrorObject("cannot redeclare constant: %s", node.Name.Value)
+		}
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		nameFunction(node.Value, val, node.Name.Value)
+		env.SetConstant(node.Name.Value, val)
+
+	case *ast.ClassStatement:
+		if env.IsConstant(node.Name.Value) {
+			return newErrorObject("cannot redeclare constant: %s", node.Name.Value)
+		}
+		env.Set(node.Name.Value, newClass(node, env))
+
+	case *ast.BreakStatement:
+		if node.Label != nil {
+			return &object.Break{Label: node.Label.Value}
+		}
+		return &object.Break{}
+
+	case *ast.ContinueStatement:
+		if node.Label != nil {
+			return &object.Continue{Label: node.Label.Value}
+		}
+		return &object.Continue{}
+
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return newThrownError(val)
+
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -35,6 +94,15 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+
+	case *ast.ForInStatement:
+		return evalForInStatement(node, env)
+
 	case *ast.BlockStatement:
 		return evalBlockStatements(node.Statements, env)
 
@@ -46,6 +114,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalPrefixExpression(node.Operator, right)
 
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" || node.Operator == "??" {
+			return evalLogicalExpression(node, env)
+		}
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -58,34 +129,54 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
+	case *ast.BigIntLiteral:
+		return &object.BigInt{Value: node.Value}
+
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 
+	case *ast.NullLiteral:
+		return NULL
+
 	case *ast.BoolenExpression:
 		return nativeBoolToBooleanObject(node.Value)
 
 	case *ast.IfExpression:
 		return evalIfExpressionObject(node, env)
 
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 
 	case *ast.FunctionLiteral:
-		params := node.Parameters
-		body := node.Body
-		return &object.FunctionObject{Parameters: params, Body: body, Env: env}
+		return &object.FunctionObject{Name: node.Name, Parameters: node.Parameters, Defaults: node.Defaults, Rest: node.Rest, Body: node.Body, Env: env}
 
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
 		if isError(function) {
 			return function
 		}
-		args := evalExpressions(node.Arguments, env)
+		if function == NULL && ast.IsOptionalChain(node.Function) {
+			return NULL
+		}
+		positional, keywordArgs := splitKeywordArguments(node.Arguments)
+		args := evalExpressions(positional, env)
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		keywords, err := evalKeywordArguments(keywordArgs, env)
+		if err != nil {
+			return err
+		}
+		return applyFunction(function, args, keywords)
 
 	case *ast.ArrayExpression:
 		elements := evalExpressions(node.Elements, env)
@@ -99,6 +190,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(left) {
 			return left
 		}
+		if left == NULL && (node.Optional || ast.IsOptionalChain(node.Left)) {
+			return NULL
+		}
 		index := Eval(node.Index, env)
 		if isError(index) {
 			return index
@@ -106,8 +200,34 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 		return evalIndexExpression(left, index)
 
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
+
+	case *ast.SliceExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		if left == NULL && (node.Optional || ast.IsOptionalChain(node.Left)) {
+			return NULL
+		}
+		return evalSliceExpression(node, left, env)
+
 	case *ast.HashLiteral:
 		return evalHashListeral(node, env)
+
+	case *ast.DotExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		if left == NULL && (node.Optional || ast.IsOptionalChain(node.Left)) {
+			return NULL
+		}
+		return evalDotExpression(left, node.Property.Value)
+
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
 	}
 	return nil
 }
@@ -116,6 +236,7 @@ func evalProgram(statements []ast.Statement, env *object.Environment) object.Obj
 	var obj object.Object
 	for _, stmt := range statements {
 		obj = Eval(stmt, env)
+		setErrorLine(obj, stmt)
 
 		switch obj := obj.(type) {
 		case *object.Return:
@@ -127,34 +248,284 @@ func evalProgram(statements []ast.Statement, env *object.Environment) object.Obj
 	return obj
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// keywordArgument is an evaluated name: value argument of a call
+type keywordArgument struct {
+	name  string
+	value object.Object
+}
+
+// splitKeywordArguments separates the trailing keyword arguments of a call from the positional ones
+func splitKeywordArguments(exps []ast.Expression) ([]ast.Expression, []*ast.KeywordArgument) {
+	for i, exp := range exps {
+		if _, ok := exp.(*ast.KeywordArgument); ok {
+			keywords := make([]*ast.KeywordArgument, 0, len(exps)-i)
+			for _, exp := range exps[i:] {
+				keywords = append(keywords, exp.(*ast.KeywordArgument))
+			}
+			return exps[:i], keywords
+		}
+	}
+	return exps, nil
+}
+
+func evalKeywordArguments(args []*ast.KeywordArgument, env *object.Environment) ([]keywordArgument, object.Object) {
+	var keywords []keywordArgument
+	for _, arg := range args {
+		val := Eval(arg.Value, env)
+		if isError(val) {
+			return nil, val
+		}
+		keywords = append(keywords, keywordArgument{name: arg.Name.Value, value: val})
+	}
+	return keywords, nil
+}
+
+func applyFunction(fn object.Object, args []object.Object, keywords []keywordArgument) object.Object {
 	switch fn := fn.(type) {
 	case *object.FunctionObject:
-		extendedEnv, ok := extendFunctionEnv(fn, args)
-		if !ok {
-			return newErrorObject("excepted no. of arguments not passed to function")
+		extendedEnv, err := extendFunctionEnv(fn, args, keywords)
+		if err != nil {
+			return err
 		}
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 
 	case *object.BuildIn:
+		if len(keywords) != 0 {
+			return newErrorObject("build in functions take no keyword arguments, got %s", keywords[0].name)
+		}
 		return fn.Value(args...)
 
+	case *object.Class:
+		return instantiate(fn, args, keywords)
+
 	default:
 		return newErrorObject("not a function: %s", fn.Type())
 	}
 
 }
 
-func extendFunctionEnv(fn *object.FunctionObject, args []object.Object) (*object.Environment, bool) {
+// extendFunctionEnv binds the parameters of fn to positional args first, then to keywords,
+// once no parameter without a default is missing the remaining parameters get their
+// default values evaluated in order in the new environment
+func extendFunctionEnv(fn *object.FunctionObject, args []object.Object, keywords []keywordArgument) (*object.Environment, object.Object) {
 	env := object.NewEnclosedEnvironment(fn.Env)
-	if len(fn.Parameters) != len(args) {
-		return nil, false
+	if len(args) > len(fn.Parameters) && fn.Rest == nil {
+		return nil, newErrorObject("%s takes %d arguments, got %d: extra arguments %s",
+			functionName(fn), len(fn.Parameters), len(args), inspectObjects(args[len(fn.Parameters):]))
+	}
+
+	bound := make(map[string]bool)
+	for i, param := range fn.Parameters {
+		if i < len(args) {
+			env.Set(param.Value, args[i])
+			bound[param.Value] = true
+		}
+	}
+	if fn.Rest != nil {
+		rest := []object.Object{}
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+		env.Set(fn.Rest.Value, &object.Array{Elements: rest})
+	}
+
+	var unexpected []string
+	for _, keyword := range keywords {
+		if !hasParameter(fn, keyword.name) {
+			unexpected = append(unexpected, keyword.name)
+			continue
+		}
+		if bound[keyword.name] {
+			return nil, newErrorObject("%s got multiple values for parameter %s", functionName(fn), keyword.name)
+		}
+		env.Set(keyword.name, keyword.value)
+		bound[keyword.name] = true
+	}
+	if len(unexpected) != 0 {
+		return nil, newErrorObject("%s got unexpected keyword arguments: %s", functionName(fn), strings.Join(unexpected, ", "))
+	}
+
+	var missing []string
+	for i, param := range fn.Parameters {
+		if !bound[param.Value] && (i >= len(fn.Defaults) || fn.Defaults[i] == nil) {
+			missing = append(missing, param.Value)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, newErrorObject("%s missing arguments: %s", functionName(fn), strings.Join(missing, ", "))
+	}
+	for i, param := range fn.Parameters {
+		if bound[param.Value] {
+			continue
+		}
+		val := Eval(fn.Defaults[i], env)
+		if isError(val) {
+			return nil, val
+		}
+		env.Set(param.Value, val)
+	}
+	return env, nil
+}
+
+func hasParameter(fn *object.FunctionObject, name string) bool {
+	for _, param := range fn.Parameters {
+		if param.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func functionName(fn *object.FunctionObject) string {
+	if fn.Name == "" {
+		return "anonymous function"
 	}
-	for id, params := range fn.Parameters {
-		env.Set(params.Value, args[id])
+	return "function " + fn.Name
+}
+
+func inspectObjects(objs []object.Object) string {
+	inspected := make([]string, len(objs))
+	for i, obj := range objs {
+		inspected[i] = obj.Inspect()
 	}
-	return env, true
+	return strings.Join(inspected, ", ")
+}
+
+// evalDestructuring binds the names of an *ast.ArrayPattern or *ast.HashPattern to the parts of val,
+// missing parts take the element default or NULL
+func evalDestructuring(pattern ast.Expression, val object.Object, env *object.Environment) object.Object {
+	for _, name := range ast.PatternNames(pattern) {
+		if env.IsConstant(name) {
+			return newErrorObject("cannot redeclare constant: %s", name)
+		}
+	}
+	switch pattern := pattern.(type) {
+	case *ast.ArrayPattern:
+		// without a rest name only the elements bound by the pattern are taken, lazy ranges stay lazy
+		var elements []object.Object
+		err := forEachElement(val, func(element object.Object) object.Object {
+			if pattern.Rest == nil && len(elements) == len(pattern.Elements) {
+				return NULL
+			}
+			elements = append(elements, element)
+			return nil
+		})
+		if isError(err) {
+			return newErrorObject("cannot destructure %s as array", val.Type())
+		}
+		for i, element := range pattern.Elements {
+			var value object.Object
+			if i < len(elements) {
+				value = elements[i]
+			}
+			if err := bindPatternElement(element, value, env); err != nil {
+				return err
+			}
+		}
+		if pattern.Rest != nil {
+			rest := []object.Object{}
+			if len(elements) > len(pattern.Elements) {
+				rest = append(rest, elements[len(pattern.Elements):]...)
+			}
+			env.Set(pattern.Rest.Value, &object.Array{Elements: rest})
+		}
+	case *ast.HashPattern:
+		for _, element := range pattern.Elements {
+			key := element.Key.String()
+			var value object.Object
+			switch val := val.(type) {
+			case *object.Hash:
+				if pair, ok := val.Pairs[(&object.String{Value: key}).HashKey()]; ok {
+					value = pair.Value
+				}
+			case *object.Instance:
+				value = val.Fields[key]
+			default:
+				return newErrorObject("cannot destructure %s as hash", val.Type())
+			}
+			if err := bindPatternElement(element, value, env); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindPatternElement binds value, or the element default when value is nil or NULL
+func bindPatternElement(element *ast.PatternElement, value object.Object, env *object.Environment) object.Object {
+	if value == nil || value == NULL {
+		value = NULL
+		if element.Default != nil {
+			value = Eval(element.Default, env)
+			if isError(value) {
+				return value
+			}
+		}
+	}
+	env.Set(element.Name.Value, value)
+	return nil
+}
+
+// nameFunction gives a function literal bound by let or const the name of its binding
+func nameFunction(value ast.Expression, obj object.Object, name string) {
+	if _, ok := value.(*ast.FunctionLiteral); !ok {
+		return
+	}
+	if fn, ok := obj.(*object.FunctionObject); ok && fn.Name == "" {
+		fn.Name = name
+	}
+}
+
+func newClass(node *ast.ClassStatement, env *object.Environment) *object.Class {
+	class := &object.Class{Name: node.Name.Value, Fields: node.Fields, Env: env}
+	class.Methods = make(map[string]*object.FunctionObject)
+	for _, method := range node.Methods {
+		class.Methods[method.Name] = &object.FunctionObject{
+			Name:       class.Name + "." + method.Name,
+			Parameters: method.Parameters,
+			Defaults:   method.Defaults,
+			Rest:       method.Rest,
+			Body:       method.Body,
+			Env:        env,
+		}
+	}
+	return class
+}
+
+// instantiate creates an instance of class, evaluates its field initializers
+// and passes args to the init method
+func instantiate(class *object.Class, args []object.Object, keywords []keywordArgument) object.Object {
+	instance := &object.Instance{Class: class, Fields: make(map[string]object.Object)}
+	for _, field := range class.Fields {
+		val := Eval(field.Value, class.Env)
+		if isError(val) {
+			return val
+		}
+		instance.Fields[field.Name.Value] = val
+	}
+
+	init, ok := class.Methods["init"]
+	if !ok {
+		if len(args) != 0 || len(keywords) != 0 {
+			return newErrorObject("class %s has no init method, got %d arguments", class.Name, len(args)+len(keywords))
+		}
+		return instance
+	}
+	result := applyFunction(bindMethod(instance, init), args, keywords)
+	if isError(result) {
+		return result
+	}
+	return instance
+}
+
+// bindMethod returns a copy of method whose enclosed environment binds self to instance
+func bindMethod(instance *object.Instance, method *object.FunctionObject) *object.FunctionObject {
+	env := object.NewEnclosedEnvironment(method.Env)
+	env.Set("self", instance)
+	bound := *method
+	bound.Env = env
+	return &bound
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -171,16 +542,32 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 	if leftType == object.INTEGER_OBJ && rightType == object.INTEGER_OBJ {
 		return evalIntegerInfixExpression(operator, left, right)
 	}
+	if isInteger(left) && isInteger(right) {
+		return evalBigIntInfixExpression(operator, left, right)
+	}
+	if isNumber(left) && isNumber(right) {
+		return evalFloatInfixExpression(operator, left, right)
+	}
 	if leftType == object.BOOLEAN_OBJ && rightType == object.BOOLEAN_OBJ {
 		return evalBooleanInfixExpression(operator, left, right)
 	}
 	if leftType == object.STRING_OBJ && rightType == object.STRING_OBJ {
 		return evalStringInfixExpression(operator, left, right)
 	}
+	if leftType == rightType && (leftType == object.ARRAY_OBJ || leftType == object.HASH_OBJ) {
+		return evalStructuralInfixExpression(operator, left, right)
+	}
+	if (left == NULL || right == NULL) && (operator == "==" || operator == "!=") {
+		return nativeBoolToBooleanObject((left == right) == (operator == "=="))
+	}
+	if leftType == rightType && (operator == "==" || operator == "!=") {
+		// instances, functions, modules and ranges compare like match does
+		return nativeBoolToBooleanObject(objectsEqual(left, right) == (operator == "=="))
+	}
 	if leftType != rightType {
-		return newErrorObject("type mismatch: %s %s %s", leftType, operator, rightType)
+		return newTypeErrorObject("type mismatch: %s %s %s", leftType, operator, rightType)
 	}
-	return newErrorObject("unknown operator: %s %s %s", leftType, operator, rightType)
+	return newTypeErrorObject("unknown operator: %s %s %s", leftType, operator, rightType)
 }
 
 func evalPrefixExpression(operator string, right object.Object) object.Object {
@@ -189,8 +576,10 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 		return evalBangOperatorPrefixExpression(right)
 	case "-":
 		return evalMinusPrefixExpression(right)
+	case "~":
+		return evalBitNotPrefixExpression(right)
 	default:
-		return newErrorObject("unknown operator: %s%s", operator, right.Type())
+		return newTypeErrorObject("unknown operator: %s%s", operator, right.Type())
 	}
 }
 
@@ -198,36 +587,156 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	leftValue := left.(*object.Integer).Value
 	rightValue := right.(*object.Integer).Value
 	switch operator {
+	case "+", "-", "*":
+		result, ok := integerArithmetic(operator, leftValue, rightValue)
+		if !ok {
+			return integerOverflow(operator, left, right)
+		}
+		return &object.Integer{Value: result}
+	case "/":
+		if rightValue == 0 {
+			return newErrorObject("division by zero: %d / 0", leftValue)
+		}
+		if leftValue == math.MinInt64 && rightValue == -1 {
+			return integerOverflow(operator, left, right)
+		}
+		return &object.Integer{Value: leftValue / rightValue}
+	case "%":
+		if rightValue == 0 {
+			return newErrorObject("division by zero: %d %% 0", leftValue)
+		}
+		return &object.Integer{Value: leftValue % rightValue}
+	case "&":
+		return &object.Integer{Value: leftValue & rightValue}
+	case "|":
+		return &object.Integer{Value: leftValue | rightValue}
+	case "^":
+		return &object.Integer{Value: leftValue ^ rightValue}
+	case ">>":
+		if rightValue < 0 {
+			return newErrorObject("negative shift count: %d >> %d", leftValue, rightValue)
+		}
+		return &object.Integer{Value: leftValue >> rightValue}
+	case "<<", "**":
+		// computed as BigInt and narrowed again, the result easily leaves the int64 range
+		result := evalBigIntInfixExpression(operator, left, right)
+		if _, ok := result.(*object.BigInt); ok && CheckedArithmetic {
+			return newErrorObject("integer overflow: %d %s %d", leftValue, operator, rightValue)
+		}
+		return result
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	default:
+		return newTypeErrorObject("unknown operator : %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// integerOverflow reports an int64 overflow in checked mode and otherwise redoes the operation as a BigInt
+func integerOverflow(operator string, left, right object.Object) object.Object {
+	if CheckedArithmetic {
+		return newErrorObject("integer overflow: %s %s %s", left.Inspect(), operator, right.Inspect())
+	}
+	return evalBigIntInfixExpression(operator, left, right)
+}
+
+// integerArithmetic applies + - or * with int64 wrapping, ok is false when the result overflowed
+func integerArithmetic(operator string, left, right int64) (result int64, ok bool) {
+	switch operator {
+	case "+":
+		result = left + right
+		return result, (right >= 0) == (result >= left)
+	case "-":
+		result = left - right
+		return result, (right >= 0) == (result <= left)
+	default:
+		result = left * right
+		if left == 0 || right == 0 {
+			return result, true
+		}
+		if (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64) {
+			return result, false
+		}
+		return result, result/right == left
+	}
+}
+
+// evalFloatInfixExpression handles two floats or a float mixed with an integer
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
+	switch operator {
 	case "+":
-		return &object.Integer{Value: leftValue + rightValue}
+		return &object.Float{Value: leftValue + rightValue}
 	case "-":
-		return &object.Integer{Value: leftValue - rightValue}
+		return &object.Float{Value: leftValue - rightValue}
 	case "*":
-		return &object.Integer{Value: leftValue * rightValue}
+		return &object.Float{Value: leftValue * rightValue}
 	case "/":
-		return &object.Integer{Value: leftValue / rightValue}
+		return &object.Float{Value: leftValue / rightValue}
 	case "%":
-		return &object.Integer{Value: leftValue % rightValue}
+		return &object.Float{Value: math.Mod(leftValue, rightValue)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftValue, rightValue)}
 	case "<":
 		return nativeBoolToBooleanObject(leftValue < rightValue)
 	case ">":
 		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
 	case "!=":
 		return nativeBoolToBooleanObject(leftValue != rightValue)
 	case "==":
 		return nativeBoolToBooleanObject(leftValue == rightValue)
 	default:
-		return newErrorObject("unknown operator : %s %s %s", left.Type(), operator, right.Type())
+		return newTypeErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
-	}
 	leftValue := left.(*object.String).Value
 	rightValue := right.(*object.String).Value
-	return &object.String{Value: leftValue + rightValue}
+	switch operator {
+	case "+":
+		return &object.String{Value: leftValue + rightValue}
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	default:
+		return newTypeErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalStructuralInfixExpression compares arrays and hashes element by element
+func evalStructuralInfixExpression(operator string, left, right object.Object) object.Object {
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(objectsEqual(left, right))
+	case "!=":
+		return nativeBoolToBooleanObject(!objectsEqual(left, right))
+	default:
+		return newTypeErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
 }
 
 func evalBooleanInfixExpression(operator string, left, right object.Object) object.Object {
@@ -236,17 +745,29 @@ func evalBooleanInfixExpression(operator string, left, right object.Object) obje
 		return nativeBoolToBooleanObject(left == right) // pointer comparsion
 	case "!=":
 		return nativeBoolToBooleanObject(left != right)
-	case "&&":
-		leftObj := left.(*object.Boolean)
-		rightObj := right.(*object.Boolean)
-		return nativeBoolToBooleanObject(leftObj.Value && rightObj.Value)
-	case "||":
-		leftObj := left.(*object.Boolean)
-		rightObj := right.(*object.Boolean)
-		return nativeBoolToBooleanObject(leftObj.Value || rightObj.Value)
 	default:
-		return newErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeErrorObject("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalLogicalExpression short-circuits &&, || and ??, the operand that decides the
+// result is returned as is, truthiness follows isTruthy like the if expression
+// and ?? only falls back to its right operand when the left one is null
+func evalLogicalExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+	if node.Operator == "??" && left != NULL {
+		return left
+	}
+	if node.Operator == "&&" && !isTruthy(left) {
+		return left
 	}
+	if node.Operator == "||" && isTruthy(left) {
+		return left
+	}
+	return Eval(node.Right, env)
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
@@ -255,6 +776,8 @@ func evalIndexExpression(left, index object.Object) object.Object {
 		return evalArrayIndexExpression(left, index)
 	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalStringIndexExpression(left, index)
+	case left.Type() == object.RANGE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalRangeIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
@@ -264,9 +787,8 @@ func evalIndexExpression(left, index object.Object) object.Object {
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrayObject := array.(*object.Array)
-	i := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
-	if i < 0 || i > max {
+	i, ok := normalizeIndex(index.(*object.Integer).Value, len(arrayObject.Elements))
+	if !ok {
 		return NULL
 	}
 	return arrayObject.Elements[i]
@@ -274,15 +796,143 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 
 func evalStringIndexExpression(str, index object.Object) object.Object {
 	stringObj := str.(*object.String)
-	i := index.(*object.Integer).Value
 	r := []rune(stringObj.Value)
-	max := int64(len(r) - 1)
-	if i < 0 || i > max {
+	i, ok := normalizeIndex(index.(*object.Integer).Value, len(r))
+	if !ok {
 		return NULL
 	}
 	return &object.String{Value: string(r[i])}
 }
 
+func evalRangeIndexExpression(rangeObj, index object.Object) object.Object {
+	r := rangeObj.(*object.Range)
+	i, ok := normalizeIndex(index.(*object.Integer).Value, int(r.Len()))
+	if !ok {
+		return NULL
+	}
+	return &object.Integer{Value: r.At(i)}
+}
+
+// evalRangeExpression creates a lazy range, the step defaults to 1
+func evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	bounds := []int64{0, 0, 1}
+	for i, part := range []ast.Expression{node.Start, node.End, node.Step} {
+		if part == nil {
+			continue
+		}
+		val := Eval(part, env)
+		if isError(val) {
+			return val
+		}
+		integer, ok := val.(*object.Integer)
+		if !ok {
+			return newErrorObject("range bounds and step must be INTEGER, got %s", val.Type())
+		}
+		bounds[i] = integer.Value
+	}
+	if bounds[2] == 0 {
+		return newErrorObject("range step cannot be zero")
+	}
+	r := &object.Range{Start: bounds[0], End: bounds[1], Step: bounds[2], Exclusive: node.Exclusive}
+	if _, ok := r.Count(); !ok {
+		return newErrorObject("range %s has too many elements", r.Inspect())
+	}
+	return r
+}
+
+// normalizeIndex resolves a negative index counting from the end, ok is false when it is out of range
+func normalizeIndex(i int64, length int) (int64, bool) {
+	if i < 0 {
+		i += int64(length)
+	}
+	return i, i >= 0 && i < int64(length)
+}
+
+// evalSliceExpression copies the elements of an array or the runes of a string selected by
+// start, end and step, negative bounds count from the end and out of range bounds are clamped
+func evalSliceExpression(node *ast.SliceExpression, left object.Object, env *object.Environment) object.Object {
+	var length int
+	var runes []rune
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		runes = []rune(left.Value)
+		length = len(runes)
+	default:
+		return newErrorObject("slice operator not supported: %s", left.Type())
+	}
+
+	bounds := make([]*int64, 3)
+	for i, part := range []ast.Expression{node.Start, node.End, node.Step} {
+		if part == nil {
+			continue
+		}
+		val := Eval(part, env)
+		if isError(val) {
+			return val
+		}
+		integer, ok := val.(*object.Integer)
+		if !ok {
+			return newErrorObject("slice indices must be INTEGER, got %s", val.Type())
+		}
+		bounds[i] = &integer.Value
+	}
+	indices, err := sliceIndices(length, bounds[0], bounds[1], bounds[2])
+	if err != nil {
+		return err
+	}
+
+	if left, ok := left.(*object.Array); ok {
+		elements := make([]object.Object, len(indices))
+		for i, index := range indices {
+			elements[i] = left.Elements[index]
+		}
+		return &object.Array{Elements: elements}
+	}
+	out := make([]rune, len(indices))
+	for i, index := range indices {
+		out[i] = runes[index]
+	}
+	return &object.String{Value: string(out)}
+}
+
+// sliceIndices returns the positions selected by a slice of a sequence of length elements,
+// nil bounds take their defaults which depend on the direction of step
+func sliceIndices(length int, start, end, step *int64) ([]int64, object.Object) {
+	n := int64(length)
+	stride := int64(1)
+	if step != nil {
+		stride = *step
+	}
+	if stride == 0 {
+		return nil, newErrorObject("slice step cannot be zero")
+	}
+	// lowest and highest are the bounds a start or end is clamped to,
+	// -1 stands for before the first element when walking backwards
+	first, last, lowest, highest := int64(0), n, int64(0), n
+	if stride < 0 {
+		first, last, lowest, highest = n-1, -1, -1, n-1
+	}
+	clamp := func(bound *int64, fallback int64) int64 {
+		if bound == nil {
+			return fallback
+		}
+		i := *bound
+		if i < 0 {
+			i += n
+		}
+		return max(lowest, min(i, highest))
+	}
+	from, to := clamp(start, first), clamp(end, last)
+
+	indices := []int64{}
+	for i := from; (stride > 0 && i < to) || (stride < 0 && i > to); i += stride {
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
+
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hastObject := hash.(*object.Hash)
 	key, ok := index.(object.Hashable)
@@ -296,9 +946,150 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 	return pair.Value
 }
 
+func evalDotExpression(left object.Object, name string) object.Object {
+	switch left := left.(type) {
+	case *object.Instance:
+		if val, ok := left.Fields[name]; ok {
+			return val
+		}
+		if method, ok := left.Class.Methods[name]; ok {
+			return bindMethod(left, method)
+		}
+		return newErrorObject("undefined property %s on %s", name, left.Class.Name)
+	case *object.Module:
+		if left.Exports[name] {
+			val, _ := left.Env.Get(name)
+			return val
+		}
+		return newErrorObject("module %s has no export %s", left.Path, name)
+	default:
+		return newErrorObject("property access not supported: %s", left.Type())
+	}
+}
+
+// evalAssignExpression updates the binding where it was defined, walking the outer environments
+func evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		scope := env.Resolve(target.Value)
+		if scope == nil {
+			return newErrorObject("cannot assign to undefined variable: %s", target.Value)
+		}
+		if scope.IsConstant(target.Value) {
+			return newErrorObject("cannot assign to constant: %s", target.Value)
+		}
+		current, _ := scope.Get(target.Value)
+		val := evalAssignedValue(node, current, env)
+		if isError(val) {
+			return val
+		}
+		scope.Set(target.Value, val)
+		return val
+
+	case *ast.DotExpression:
+		left := Eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+		instance, ok := left.(*object.Instance)
+		if !ok {
+			return newErrorObject("property assignment not supported: %s", left.Type())
+		}
+		current, ok := instance.Fields[target.Property.Value]
+		if !ok && node.Operator != "=" {
+			return newErrorObject("undefined property %s on %s", target.Property.Value, instance.Class.Name)
+		}
+		val := evalAssignedValue(node, current, env)
+		if isError(val) {
+			return val
+		}
+		instance.Fields[target.Property.Value] = val
+		return val
+
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(target.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexAssignment(node, left, index, env)
+
+	default:
+		return newErrorObject("invalid assignment target: %s", node.Target.String())
+	}
+}
+
+// evalIndexAssignment mutates the elements of an array or the pairs of a hash in place
+func evalIndexAssignment(node *ast.AssignExpression, left, index object.Object, env *object.Environment) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		integer, ok := index.(*object.Integer)
+		if !ok {
+			return newErrorObject("array index must be INTEGER, got %s", index.Type())
+		}
+		i, ok := normalizeIndex(integer.Value, len(left.Elements))
+		if !ok {
+			return newErrorObject("index out of range: %d with length %d", integer.Value, len(left.Elements))
+		}
+		val := evalAssignedValue(node, left.Elements[i], env)
+		if isError(val) {
+			return val
+		}
+		left.Elements[i] = val
+		return val
+
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newErrorObject("unusable as hash key: %s", index.Type())
+		}
+		hashed := key.HashKey()
+		pair, ok := left.Pairs[hashed]
+		if !ok && node.Operator != "=" {
+			return newErrorObject("key not found: %s", index.Inspect())
+		}
+		val := evalAssignedValue(node, pair.Value, env)
+		if isError(val) {
+			return val
+		}
+		left.Set(hashed, object.HashPair{Key: index, Value: val})
+		return val
+
+	default:
+		return newErrorObject("index assignment not supported: %s", left.Type())
+	}
+}
+
+// evalAssignedValue evaluates the right hand side of an assignment,
+// compound operators combine it with the current value of the target
+func evalAssignedValue(node *ast.AssignExpression, current object.Object, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) || node.Operator == "=" {
+		return val
+	}
+	return evalInfixExpression(strings.TrimSuffix(node.Operator, "="), current, val)
+}
+
 func evalHashListeral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
-	for keyNode, valueNode := range node.Pairs {
+	result := object.NewHash()
+	for _, keyNode := range node.Order {
+		if spread, ok := keyNode.(*ast.SpreadExpression); ok {
+			val := Eval(spread.Value, env)
+			if isError(val) {
+				return val
+			}
+			hash, ok := val.(*object.Hash)
+			if !ok {
+				return newErrorObject("cannot spread %s into hash", val.Type())
+			}
+			for _, hashed := range hash.Keys {
+				result.Set(hashed, hash.Pairs[hashed])
+			}
+			continue
+		}
 		key := Eval(keyNode, env)
 		if isError(key) {
 			return key
@@ -308,24 +1099,44 @@ func evalHashListeral(node *ast.HashLiteral, env *object.Environment) object.Obj
 		if !ok {
 			return newErrorObject("unusable as hash key : %s", key.Type())
 		}
-		value := Eval(valueNode, env)
+		value := Eval(node.Pairs[keyNode], env)
 		if isError(value) {
 			return value
 		}
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		result.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
 	}
-	return &object.Hash{Pairs: pairs}
+	return result
 }
 
 func evalMinusPrefixExpression(right object.Object) object.Object {
 	switch right := right.(type) {
 	case *object.Integer:
+		if right.Value == math.MinInt64 {
+			if CheckedArithmetic {
+				return newErrorObject("integer overflow: -(%s)", right.Inspect())
+			}
+			return &object.BigInt{Value: new(big.Int).Neg(big.NewInt(right.Value))}
+		}
 		return &object.Integer{Value: -right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Neg(right.Value))
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
 	case *object.ErrorObject:
 		return right
 	default:
-		return newErrorObject("unknown operator: -%s", right.Type())
+		return newTypeErrorObject("unknown operator: -%s", right.Type())
+	}
+}
+
+func evalBitNotPrefixExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Not(right.Value))
+	default:
+		return newTypeErrorObject("unknown operator: ~%s", right.Type())
 	}
 }
 
@@ -356,13 +1167,252 @@ func evalIfExpressionObject(ie *ast.IfExpression, env *object.Environment) objec
 	}
 }
 
+// evalMatchExpression evaluates the first arm with a pattern structurally equal to
+// the subject, patterns are evaluated in order until one matches
+func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+	for _, arm := range me.Arms {
+		for _, pattern := range arm.Patterns {
+			if wildcard, ok := pattern.(*ast.Identifier); ok && wildcard.Value == "_" {
+				return Eval(arm.Body, env)
+			}
+			value := Eval(pattern, env)
+			if isError(value) {
+				return value
+			}
+			if objectsEqual(subject, value) {
+				return Eval(arm.Body, env)
+			}
+		}
+	}
+	return NULL
+}
+
+// evalTryExpression runs the catch block when the try block fails with an error,
+// the finally block always runs and only replaces the result when it leaves early itself
+func evalTryExpression(ts *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(ts.Block, env)
+	if err, ok := result.(*object.ErrorObject); ok && ts.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if ts.CatchParam != nil {
+			catchEnv.Set(ts.CatchParam.Value, caughtErrorObject(err))
+		}
+		result = Eval(ts.Catch, catchEnv)
+	}
+	if ts.Finally != nil {
+		final := Eval(ts.Finally, env)
+		if final != nil {
+			switch final.Type() {
+			case object.RETURN_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+				return final
+			}
+		}
+	}
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// caughtErrorObject converts an error into the hash bound by catch
+//
+//	{"message": <string>, "line": <integer>, "kind": <string>, "value": <thrown value or null>}
+func caughtErrorObject(err *object.ErrorObject) *object.Hash {
+	value := err.Value
+	if value == nil {
+		value = NULL
+	}
+	hash := object.NewHash()
+	for _, field := range []struct {
+		name  string
+		value object.Object
+	}{
+		{"message", &object.String{Value: err.Message}},
+		{"line", &object.Integer{Value: int64(err.Line)}},
+		{"kind", &object.String{Value: err.Kind}},
+		{"value", value},
+	} {
+		key := &object.String{Value: field.name}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: field.value})
+	}
+	return hash
+}
+
+// newThrownError wraps a thrown value. Strings become the message, hashes may
+// carry "message" and "kind" keys, as the hash bound by catch does, so rethrowing keeps them
+func newThrownError(val object.Object) *object.ErrorObject {
+	err := &object.ErrorObject{Message: val.Inspect(), Kind: object.THROWN_ERROR, Value: val}
+	if hash, ok := val.(*object.Hash); ok {
+		if message, ok := hashStringValue(hash, "message"); ok {
+			err.Message = message
+		}
+		if kind, ok := hashStringValue(hash, "kind"); ok {
+			err.Kind = kind
+		}
+		if line, ok := hash.Pairs[(&object.String{Value: "line"}).HashKey()]; ok {
+			if line, ok := line.Value.(*object.Integer); ok {
+				err.Line = int(line.Value)
+			}
+		}
+		if value, ok := hash.Pairs[(&object.String{Value: "value"}).HashKey()]; ok && value.Value != NULL {
+			err.Value = value.Value
+		}
+	}
+	return err
+}
+
+func hashStringValue(hash *object.Hash, key string) (string, bool) {
+	pair, ok := hash.Pairs[(&object.String{Value: key}).HashKey()]
+	if !ok {
+		return "", false
+	}
+	str, ok := pair.Value.(*object.String)
+	if !ok {
+		return "", false
+	}
+	return str.Value, true
+}
+
+// every iteration of a while statement runs its body in a fresh scope
+func evalWhileStatement(ws *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(ws.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return NULL
+		}
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		if result, stop := evalLoopBody(ws.Body, ws.Label, iterationEnv); stop {
+			return result
+		}
+	}
+}
+
+// the loop variables of a for statement live in their own scope,
+// every iteration runs the body in a fresh scope enclosed by it
+func evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Object {
+	loopEnv := object.NewEnclosedEnvironment(env)
+	if fs.Init != nil {
+		init := Eval(fs.Init, loopEnv)
+		if isError(init) {
+			return init
+		}
+	}
+	for {
+		if fs.Condition != nil {
+			condition := Eval(fs.Condition, loopEnv)
+			if isError(condition) {
+				return condition
+			}
+			if !isTruthy(condition) {
+				return NULL
+			}
+		}
+		iterationEnv := object.NewEnclosedEnvironment(loopEnv)
+		if result, stop := evalLoopBody(fs.Body, fs.Label, iterationEnv); stop {
+			return result
+		}
+		if fs.Update != nil {
+			update := Eval(fs.Update, loopEnv)
+			if isError(update) {
+				return update
+			}
+		}
+	}
+}
+
+// every iteration of a for-in statement gets a fresh scope holding the loop variable
+func evalForInStatement(fs *ast.ForInStatement, env *object.Environment) object.Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+	result := forEachElement(iterable, func(element object.Object) object.Object {
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		iterationEnv.Set(fs.Variable.Value, element)
+		if result, stop := evalLoopBody(fs.Body, fs.Label, iterationEnv); stop {
+			return result
+		}
+		return nil
+	})
+	if result != nil {
+		return result
+	}
+	return NULL
+}
+
+// evalLoopBody evaluates one iteration of a loop. stop reports that the loop has to end,
+// result is then either NULL or the return value, error or control signal of an outer loop
+// to pass on. break and continue without label belong to the innermost loop.
+func evalLoopBody(body *ast.BlockStatement, label *ast.Identifier, env *object.Environment) (result object.Object, stop bool) {
+	ownsLabel := func(target string) bool {
+		return target == "" || (label != nil && label.Value == target)
+	}
+	switch result := Eval(body, env).(type) {
+	case *object.Break:
+		if ownsLabel(result.Label) {
+			return NULL, true
+		}
+		return result, true
+	case *object.Continue:
+		if ownsLabel(result.Label) {
+			return nil, false
+		}
+		return result, true
+	case *object.Return, *object.ErrorObject:
+		return result, true
+	}
+	return nil, false
+}
+
+// forEachElement calls fn with every element of an iterable object, arrays yield
+// their elements, strings their characters and hashes their keys in insertion order.
+// Iteration stops at the first non nil object returned by fn, which is passed on.
+func forEachElement(iterable object.Object, fn func(object.Object) object.Object) object.Object {
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for _, element := range iterable.Elements {
+			if result := fn(element); result != nil {
+				return result
+			}
+		}
+	case *object.String:
+		for _, r := range iterable.Value {
+			if result := fn(&object.String{Value: string(r)}); result != nil {
+				return result
+			}
+		}
+	case *object.Hash:
+		for _, pair := range iterable.OrderedPairs() {
+			if result := fn(pair.Key); result != nil {
+				return result
+			}
+		}
+	case *object.Range:
+		for i := int64(0); i < iterable.Len(); i++ {
+			if result := fn(&object.Integer{Value: iterable.At(i)}); result != nil {
+				return result
+			}
+		}
+	default:
+		return newErrorObject("object is not iterable: %s", iterable.Type())
+	}
+	return nil
+}
+
 func evalBlockStatements(statements []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 	for _, stmt := range statements {
 		result = Eval(stmt, env)
+		setErrorLine(result, stmt)
 		if result != nil {
 			rt := result.Type()
-			if rt == object.RETURN_OBJ || rt == object.ERROR_OBJ {
+			if rt == object.RETURN_OBJ || rt == object.ERROR_OBJ || rt == object.BREAK_OBJ || rt == object.CONTINUE_OBJ {
 				return result
 			}
 		}
@@ -381,14 +1431,29 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 	return newErrorObject("identifier not found: %s", node.Value)
 }
 
+// evalExpressions evaluates exps in order, a spread expression contributes every element of its iterable
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
 	var results []object.Object
 	for _, e := range exps {
+		spread, isSpread := e.(*ast.SpreadExpression)
+		if isSpread {
+			e = spread.Value
+		}
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
 		}
-		results = append(results, evaluated)
+		if !isSpread {
+			results = append(results, evaluated)
+			continue
+		}
+		err := forEachElement(evaluated, func(element object.Object) object.Object {
+			results = append(results, element)
+			return nil
+		})
+		if err != nil {
+			return []object.Object{err}
+		}
 	}
 	return results
 }
@@ -414,13 +1479,157 @@ func isTruthy(obj object.Object) bool {
 			return false
 		}
 		return true
+	case *object.BigInt:
+		return obj.Value.Sign() != 0
+	case *object.Float:
+		return obj.Value != 0
+	default:
+		return false
+	}
+}
+
+// objectsEqual reports whether two objects are structurally equal, integers and floats
+// compare by value, ranges by their bounds and step, arrays and hashes by their contents
+// and other objects by identity
+func objectsEqual(left, right object.Object) bool {
+	return structurallyEqual(left, right, make(map[[2]object.Object]bool))
+}
+
+// structurallyEqual implements objectsEqual, comparing holds the pairs of arrays and hashes
+// being compared further out, meeting one of them again means a cycle that is taken as equal
+func structurallyEqual(left, right object.Object, comparing map[[2]object.Object]bool) bool {
+	if left == right {
+		return true
+	}
+	if isNumber(left) && isNumber(right) {
+		if isInteger(left) && isInteger(right) {
+			return toBigInt(left).Cmp(toBigInt(right)) == 0
+		}
+		return toFloat(left) == toFloat(right)
+	}
+	switch left := left.(type) {
+	case *object.String:
+		right, ok := right.(*object.String)
+		return ok && left.Value == right.Value
+	case *object.Range:
+		right, ok := right.(*object.Range)
+		return ok && *left == *right
+	case *object.Array:
+		right, ok := right.(*object.Array)
+		if !ok || len(left.Elements) != len(right.Elements) {
+			return false
+		}
+		compared := [2]object.Object{left, right}
+		if comparing[compared] {
+			return true
+		}
+		comparing[compared] = true
+		defer delete(comparing, compared)
+		for i, element := range left.Elements {
+			if !structurallyEqual(element, right.Elements[i], comparing) {
+				return false
+			}
+		}
+		return true
+	case *object.Hash:
+		right, ok := right.(*object.Hash)
+		if !ok || len(left.Pairs) != len(right.Pairs) {
+			return false
+		}
+		compared := [2]object.Object{left, right}
+		if comparing[compared] {
+			return true
+		}
+		comparing[compared] = true
+		defer delete(comparing, compared)
+		for key, pair := range left.Pairs {
+			other, ok := right.Pairs[key]
+			if !ok || !structurallyEqual(pair.Value, other.Value, comparing) {
+				return false
+			}
+		}
+		return true
 	default:
 		return false
 	}
 }
 
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.BigInt, *object.Float:
+		return true
+	default:
+		return false
+	}
+}
+
+// toFloat converts an object accepted by isNumber to float64
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInt:
+		value, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return value
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
+}
+
 func newErrorObject(format string, a ...any) *object.ErrorObject {
-	return &object.ErrorObject{Message: fmt.Sprintf(format, a...)}
+	return &object.ErrorObject{Message: fmt.Sprintf(format, a...), Kind: object.RUNTIME_ERROR}
+}
+
+// newTypeErrorObject reports operands whose types do not support an operator
+func newTypeErrorObject(format string, a ...any) *object.ErrorObject {
+	return &object.ErrorObject{Message: fmt.Sprintf(format, a...), Kind: object.TYPE_ERROR}
+}
+
+// setErrorLine records the line of stmt on an error that has no line yet,
+// errors pass through the innermost statement first so they keep the most precise line
+func setErrorLine(obj object.Object, stmt ast.Statement) {
+	err, ok := obj.(*object.ErrorObject)
+	if !ok || err.Line != 0 {
+		return
+	}
+	err.Line = statementLine(stmt)
+}
+
+func statementLine(stmt ast.Statement) int {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		return stmt.Token.Line
+	case *ast.ConstStatement:
+		return stmt.Token.Line
+	case *ast.ReturnStatement:
+		return stmt.Token.Line
+	case *ast.ExpressionStatement:
+		return stmt.Token.Line
+	case *ast.BlockStatement:
+		return stmt.Token.Line
+	case *ast.WhileStatement:
+		return stmt.Token.Line
+	case *ast.ForStatement:
+		return stmt.Token.Line
+	case *ast.ForInStatement:
+		return stmt.Token.Line
+	case *ast.ClassStatement:
+		return stmt.Token.Line
+	case *ast.BreakStatement:
+		return stmt.Token.Line
+	case *ast.ContinueStatement:
+		return stmt.Token.Line
+	case *ast.ThrowStatement:
+		return stmt.Token.Line
+	case *ast.ImportStatement:
+		return stmt.Token.Line
+	case *ast.ExportStatement:
+		return stmt.Token.Line
+	default:
+		return 0
+	}
 }
 
 func isError(node object.Object) bool {
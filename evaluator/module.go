@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sachinaralapura/shoebill/ast"
+	filereader "github.com/sachinaralapura/shoebill/fileReader"
+	"github.com/sachinaralapura/shoebill/lexer"
+	"github.com/sachinaralapura/shoebill/object"
+	"github.com/sachinaralapura/shoebill/parser"
+)
+
+var (
+	modules   = make(map[string]*object.Module) // evaluated modules by absolute path
+	importing []string                          // modules being evaluated, innermost last
+)
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	if env.IsConstant(node.Alias.Value) {
+		return newErrorObject("cannot redeclare constant: %s", node.Alias.Value)
+	}
+	path, err := resolveModulePath(node.Path.Value, env.File())
+	if err != nil {
+		return newErrorObject("cannot import %q: %s", node.Path.Value, err)
+	}
+	if len(importing) == 0 && env.File() != "" {
+		// the entry file is not loaded through loadModule, importing it again is a cycle too
+		if entry, err := filepath.Abs(env.File()); err == nil {
+			importing = []string{entry}
+			defer func() { importing = nil }()
+		}
+	}
+	module := loadModule(path)
+	if isError(module) {
+		return module
+	}
+	env.Set(node.Alias.Value, module)
+	return nil
+}
+
+// resolveModulePath makes an import path absolute, relative paths are resolved
+// against the directory of the importing file or the working directory
+func resolveModulePath(path, importer string) (string, error) {
+	if !filepath.IsAbs(path) {
+		dir := filepath.Dir(importer)
+		if importer == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return "", err
+			}
+			dir = wd
+		}
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Abs(path)
+}
+
+// loadModule evaluates the file at path in its own environment the first time it
+// is imported, later imports share the cached module
+func loadModule(path string) object.Object {
+	if module, ok := modules[path]; ok {
+		return module
+	}
+	for i, pending := range importing {
+		if pending == path {
+			cycle := append(append([]string{}, importing[i:]...), path)
+			return newErrorObject("cyclic import: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	program, err := parseModule(path)
+	if err != nil {
+		return newErrorObject("cannot import %s: %s", path, err)
+	}
+
+	importing = append(importing, path)
+	defer func() { importing = importing[:len(importing)-1] }()
+
+	env := object.NewEnvirnoment()
+	env.SetFile(path)
+	if result := Eval(program, env); isError(result) {
+		err := result.(*object.ErrorObject)
+		return &object.ErrorObject{Message: fmt.Sprintf("in module %s: %s", path, err.Inspect()), Kind: err.Kind, Value: err.Value}
+	}
+
+	module := &object.Module{Path: path, Env: env, Exports: make(map[string]bool)}
+	for _, stmt := range program.Statements {
+		export, ok := stmt.(*ast.ExportStatement)
+		if !ok {
+			continue
+		}
+		for _, name := range boundNames(export.Statement) {
+			if _, ok := env.Get(name); ok {
+				module.Exports[name] = true
+			}
+		}
+	}
+	modules[path] = module
+	return module
+}
+
+// parseModule reads and parses a module file the same way main reads the program
+func parseModule(path string) (*ast.Program, error) {
+	fileToLexChan := make(chan []byte)
+	fileReader := filereader.New(fileToLexChan)
+	fileReader.FileName = path
+	if err := fileReader.Open(); err != nil {
+		return nil, err
+	}
+	go fileReader.ReadChunk()
+
+	l := lexer.New(fileToLexChan)
+	l.LoadBuffer()
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, fmt.Errorf("parse errors: %s", strings.Join(p.Errors(), "; "))
+	}
+	return program, nil
+}
+
+// boundNames returns the names a statement binds in its environment
+func boundNames(stmt ast.Statement) []string {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		if stmt.Pattern != nil {
+			return ast.PatternNames(stmt.Pattern)
+		}
+		return []string{stmt.Name.Value}
+	case *ast.ConstStatement:
+		return []string{stmt.Name.Value}
+	case *ast.ClassStatement:
+		return []string{stmt.Name.Value}
+	default:
+		return nil
+	}
+}
@@ -1,6 +1,7 @@
 package filereader
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,12 +12,13 @@ import (
 type FileReader struct {
 	FileName string
 	sendChan chan<- []byte
+	file     *os.File
 }
 
-// sets the FileName from the command line argument
+// sets the FileName from the first command line argument left after flag parsing
 func (filereader *FileReader) GetFileName() (string, error) {
-	if len(os.Args) > 1 {
-		return os.Args[1], nil
+	if flag.NArg() > 0 {
+		return flag.Arg(0), nil
 	}
 	return "", fmt.Errorf("Command Line argument not found")
 }
@@ -29,11 +31,24 @@ func (fr *FileReader) SetFileNameFromArgs() {
 	fr.FileName = filename
 }
 
-func (filereader *FileReader) ReadChunk() {
+// Open opens FileName so a missing file can be reported before ReadChunk runs
+func (filereader *FileReader) Open() error {
 	file, err := os.Open(filereader.FileName)
 	if err != nil {
-		log.Fatalf("Error opening file %s : file not found", filereader.FileName)
+		return fmt.Errorf("Error opening file %s : file not found", filereader.FileName)
+	}
+	filereader.file = file
+	return nil
+}
+
+// ReadChunk sends the file to the lexer chunk by chunk, opening it first if Open was not called
+func (filereader *FileReader) ReadChunk() {
+	if filereader.file == nil {
+		if err := filereader.Open(); err != nil {
+			log.Fatal(err)
+		}
 	}
+	file := filereader.file
 	log.Println("reading file : ", filereader.FileName)
 	defer file.Close()
 
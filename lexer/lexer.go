@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/sachinaralapura/shoebill/token"
 )
@@ -27,6 +29,9 @@ type Lexer struct {
 	ch           rune
 
 	recieveChan <-chan []byte
+	next        []rune // chunk received ahead of time by peekChar
+	hasNext     bool
+	partial     []byte // incomplete utf-8 sequence at the end of the last chunk
 
 	currentLineNumber int
 	tokens            []token.Token
@@ -40,15 +45,52 @@ func (l *Lexer) String() string {
 	return out.String()
 }
 
+// receiveChunk returns the next chunk of runes, either the one already fetched
+// by peekChar or a new one from the recieveChan. A multi-byte character split
+// between two chunks is held back until its remaining bytes arrive.
+func (l *Lexer) receiveChunk() ([]rune, bool) {
+	if l.hasNext {
+		l.hasNext = false
+		return l.next, true
+	}
+	if l.recieveChan == nil {
+		return nil, false
+	}
+	for {
+		data, ok := <-l.recieveChan
+		if !ok {
+			if len(l.partial) == 0 {
+				return nil, false
+			}
+			runes := []rune(string(l.partial))
+			l.partial = nil
+			return runes, true
+		}
+		data = append(l.partial, data...)
+		complete := len(data)
+		for start := len(data) - 1; start >= 0 && start >= len(data)-utf8.UTFMax; start-- {
+			if utf8.RuneStart(data[start]) {
+				if !utf8.FullRune(data[start:]) {
+					complete = start
+				}
+				break
+			}
+		}
+		l.partial = append([]byte(nil), data[complete:]...)
+		if complete > 0 {
+			return []rune(string(data[:complete])), true
+		}
+	}
+}
+
 // LoadBuffer reads a chunk of data from the recieveChan
 func (l *Lexer) LoadBuffer() bool {
 
 	// recieve chunk from the channel
-	data, ok := <-l.recieveChan
+	runes, ok := l.receiveChunk()
 	if !ok {
 		return false
 	}
-	runes := []rune(string(data))
 
 	// swap buffers
 	if l.CurBuf == &l.buffer1 {
@@ -71,13 +113,17 @@ func (l *Lexer) LoadBuffer() bool {
 
 // readChar reads the next character from the buffer1 and advances the read position.
 // If the end of the buffer1 is reached, it sets the current character to 0.
+// Every newline that is read past increments the current line number.
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.currentLineNumber += 1
+	}
 	if l.readPosition >= len(*l.CurBuf) {
+		// LoadBuffer already reads the first character of the new chunk
 		if l.LoadBuffer() {
-			l.ch = (*l.CurBuf)[l.readPosition]
-		} else {
-			l.ch = 0
+			return
 		}
+		l.ch = 0
 	} else {
 		l.ch = (*l.CurBuf)[l.readPosition]
 	}
@@ -86,63 +132,239 @@ func (l *Lexer) readChar() {
 }
 
 // peekChar returns the next character in the buffer1 without advancing the read position.
-// It returns 0 if the end of the buffer1 is reached.
+// At the end of the buffer1 it looks into the next chunk and returns 0 only at the end of input.
 func (l *Lexer) peekChar() rune {
-	if l.readPosition >= len(*l.CurBuf) {
-		return 0
+	if l.readPosition < len(*l.CurBuf) {
+		return (*l.CurBuf)[l.readPosition]
+	}
+	if !l.hasNext {
+		runes, ok := l.receiveChunk()
+		if !ok {
+			return 0
+		}
+		l.next, l.hasNext = runes, true
 	}
-	return (*l.CurBuf)[l.readPosition]
+	return l.next[0]
 }
 
-// readIdentifier reads an identifier from the buffer1
+// readIdentifier reads an identifier from the buffer1, the identifier may continue in the next chunk
 func (l *Lexer) readIdentifier() []rune {
-	var currentBuffer *[]rune = l.CurBuf
-	initPos := l.position
+	var identifier []rune
 	for isIdentifierCharacter(l.ch) {
+		identifier = append(identifier, l.ch)
 		l.readChar()
 	}
-	if currentBuffer != l.CurBuf {
-		prevBufPart := (*currentBuffer)[initPos:]
-		currBufPart := (*l.CurBuf)[0:l.position]
-		return append(prevBufPart, currBufPart...)
+	return identifier
+}
+
+// readNumber reads an integer or float literal, integers may carry a 0x, 0o or 0b prefix,
+// floats a fraction and an exponent, and both may use '_' between digits.
+// Decimal literals other than 0 itself do not start with 0.
+// On failure msg describes the problem, the rest of the malformed literal is still consumed.
+func (l *Lexer) readNumber() (number string, tokenType token.TokenType, msg string) {
+	var literal []rune
+	tokenType = token.INT
+	kind := "number"
+	isBaseDigit := isDigit
+	if l.ch == '0' {
+		switch l.peekChar() {
+		case 'x', 'X':
+			kind, isBaseDigit = "hexadecimal literal", isHexDigit
+		case 'o', 'O':
+			kind, isBaseDigit = "octal literal", isOctalDigit
+		case 'b', 'B':
+			kind, isBaseDigit = "binary literal", isBinaryDigit
+		}
+		if kind != "number" {
+			literal = append(literal, l.ch)
+			l.readChar()
+			literal = append(literal, l.ch)
+			l.readChar()
+		}
+	}
+	prefixLength := len(literal)
+	literal = l.readDigits(literal, isBaseDigit)
+	if kind == "number" {
+		// a '.' not followed by a digit is left alone, 1..5 is a range and not the float 1.
+		if l.ch == '.' && isDigit(l.peekChar()) {
+			tokenType = token.FLOAT
+			literal = append(literal, l.ch)
+			l.readChar()
+			literal = l.readDigits(literal, isDigit)
+		}
+		if l.ch == 'e' || l.ch == 'E' {
+			tokenType = token.FLOAT
+			literal = append(literal, l.ch)
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				literal = append(literal, l.ch)
+				l.readChar()
+			}
+			exponentStart := len(literal)
+			literal = l.readDigits(literal, isDigit)
+			if len(literal) == exponentStart && !isIdentifierCharacter(l.ch) {
+				return string(literal), tokenType, fmt.Sprintf("malformed number %q: exponent has no digits", string(literal))
+			}
+		}
+	}
+
+	if isIdentifierCharacter(l.ch) || (l.ch == '.' && isDigit(l.peekChar())) {
+		unexpected := l.ch
+		for isIdentifierCharacter(l.ch) || (l.ch == '.' && isDigit(l.peekChar())) {
+			literal = append(literal, l.ch)
+			l.readChar()
+		}
+		return string(literal), tokenType, fmt.Sprintf("malformed %s %q: unexpected %q", kind, string(literal), unexpected)
+	}
+	if len(literal) == prefixLength {
+		return string(literal), tokenType, fmt.Sprintf("malformed %s %q: no digits after prefix", kind, string(literal))
+	}
+	for i, ch := range literal {
+		if ch != '_' {
+			continue
+		}
+		if i == prefixLength || i == len(literal)-1 || !isBaseDigit(literal[i-1]) || !isBaseDigit(literal[i+1]) {
+			return string(literal), tokenType, fmt.Sprintf("malformed %s %q: '_' must separate digits", kind, string(literal))
+		}
+	}
+	// a decimal literal with a leading zero reads as octal in other languages, 0o has to be spelled out
+	if kind == "number" && len(literal) > 1 && literal[0] == '0' && (isDigit(literal[1]) || literal[1] == '_') {
+		return string(literal), tokenType, fmt.Sprintf("malformed number %q: leading zero, use 0o for octal", string(literal))
 	}
+	return string(literal), tokenType, ""
+}
 
-	return (*l.CurBuf)[initPos:l.position]
+// readDigits appends digits accepted by isBaseDigit and '_' separators to literal
+func (l *Lexer) readDigits(literal []rune, isBaseDigit func(rune) bool) []rune {
+	for isBaseDigit(l.ch) || l.ch == '_' {
+		literal = append(literal, l.ch)
+		l.readChar()
+	}
+	return literal
 }
 
-// readNumber reads a number from the buffer1
-//
-// It reads characters until it encounters a non-digit.
-func (l *Lexer) readNumber() []rune {
-	var currentBuffer *[]rune = l.CurBuf
-	initPos := l.position
-	for isDigit(l.ch) {
+// readString reads a double quoted string literal and processes its escape sequences.
+// On failure msg describes the first problem, the rest of the literal is still consumed.
+func (l *Lexer) readString() (str string, msg string) {
+	var out []rune
+	for {
 		l.readChar()
+		switch l.ch {
+		case '"':
+			return string(out), msg
+		case 0:
+			return "", "unterminated string literal"
+		case '\\':
+			l.readChar()
+			r, escapeMsg := l.readEscape()
+			if escapeMsg != "" && msg == "" {
+				msg = escapeMsg
+			}
+			if l.ch == 0 {
+				return "", "unterminated string literal"
+			}
+			out = append(out, r)
+		default:
+			out = append(out, l.ch)
+		}
 	}
-	if currentBuffer != l.CurBuf {
-		prevBufPart := (*currentBuffer)[initPos:]
-		currBufPart := (*l.CurBuf)[0:l.position]
-		return append(prevBufPart, currBufPart...)
+}
+
+// readEscape decodes the escape sequence whose first character follows the backslash
+//
+//	\n \t \r \0 \\ \" \' \u{<hex digits>}
+func (l *Lexer) readEscape() (rune, string) {
+	switch l.ch {
+	case 'n':
+		return '\n', ""
+	case 't':
+		return '\t', ""
+	case 'r':
+		return '\r', ""
+	case '0':
+		return 0, ""
+	case '\\', '"', '\'':
+		return l.ch, ""
+	case 'u':
+		if l.peekChar() != '{' {
+			return 0, "invalid unicode escape: expected '{' after \\u"
+		}
+		l.readChar()
+		var digits []rune
+		for l.peekChar() != '}' {
+			if !isHexDigit(l.peekChar()) || len(digits) == 6 {
+				return 0, "invalid unicode escape: expected 1 to 6 hex digits in \\u{...}"
+			}
+			l.readChar()
+			digits = append(digits, l.ch)
+		}
+		l.readChar() // '}'
+		code, err := strconv.ParseUint(string(digits), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return 0, fmt.Sprintf("invalid unicode escape: \\u{%s} is not a valid code point", string(digits))
+		}
+		return rune(code), ""
+	case 0:
+		return 0, "unterminated string literal"
+	default:
+		return 0, fmt.Sprintf("invalid escape sequence: \\%c", l.ch)
 	}
-	return (*l.CurBuf)[initPos:l.position]
 }
 
-func (l *Lexer) readString() []rune {
-	l.readChar() // skip '"'
-	var currentBuffer *[]rune = l.CurBuf
-	initPos := l.position
+// readRawString reads a backtick string literal verbatim, it may span several lines
+func (l *Lexer) readRawString() (str string, msg string) {
+	var out []rune
 	for {
 		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
-			break
+		switch l.ch {
+		case '`':
+			return string(out), ""
+		case 0:
+			return "", "unterminated raw string literal"
+		default:
+			out = append(out, l.ch)
 		}
 	}
-	if currentBuffer != l.CurBuf {
-		prevBufPart := (*currentBuffer)[initPos:]
-		currBufPart := (*l.CurBuf)[0:l.position]
-		return append(prevBufPart, currBufPart...)
+}
+
+// skipTrivia skips whitespace and comments, the text of each comment is returned.
+// An unterminated block comment is reported through msg at the line where it opened.
+//
+//	// line comment
+//	/* block comment */
+func (l *Lexer) skipTrivia() (trivia []string, msg string, line int) {
+	for {
+		l.skipWhiteSpace()
+		if l.ch != '/' {
+			return trivia, "", 0
+		}
+		switch l.peekChar() {
+		case '/':
+			comment := []rune{}
+			for l.ch != '\n' && l.ch != 0 {
+				comment = append(comment, l.ch)
+				l.readChar()
+			}
+			trivia = append(trivia, string(comment))
+		case '*':
+			startLine := l.currentLineNumber
+			comment := []rune{'/', '*'}
+			l.readChar()
+			l.readChar()
+			for !(l.ch == '*' && l.peekChar() == '/') {
+				if l.ch == 0 {
+					return trivia, "unterminated block comment", startLine
+				}
+				comment = append(comment, l.ch)
+				l.readChar()
+			}
+			l.readChar()
+			l.readChar()
+			trivia = append(trivia, string(comment)+"*/")
+		default:
+			return trivia, "", 0
+		}
 	}
-	return (*l.CurBuf)[initPos:l.position]
 }
 
 // skipWhiteSpace skips all whitespace characters in the input.
@@ -155,16 +377,22 @@ func (l *Lexer) skipWhiteSpace() {
 // NextToken returns the next token from the input.
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
-	if l.ch == '\n' {
-		l.currentLineNumber += 1
+	trivia, msg, line := l.skipTrivia()
+	if msg != "" {
+		tok = newToken(token.ILLEGAL, msg, line)
+		tok.Trivia = trivia
+		l.addToken(tok)
+		return tok
 	}
-	l.skipWhiteSpace()
 	curChar := string(l.ch)
 	switch l.ch {
 	case '=':
 		if l.peekChar() == l.ch {
 			l.readChar()
 			tok = newToken(token.EQUAL, curChar+string(l.ch), l.currentLineNumber)
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = newToken(token.FAT_ARROW, curChar+string(l.ch), l.currentLineNumber)
 		} else {
 			tok = newToken(token.ASSIGN, curChar, l.currentLineNumber)
 		}
@@ -179,16 +407,52 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '&' {
 			l.readChar()
 			tok = newToken(token.AND, "&&", l.currentLineNumber)
+		} else {
+			tok = newToken(token.BIT_AND, curChar, l.currentLineNumber)
 		}
 	case '|':
 		if l.peekChar() == '|' {
 			l.readChar()
 			tok = newToken(token.OR, "||", l.currentLineNumber)
+		} else {
+			tok = newToken(token.BIT_OR, curChar, l.currentLineNumber)
 		}
+	case '^':
+		tok = newToken(token.BIT_XOR, curChar, l.currentLineNumber)
+	case '~':
+		tok = newToken(token.BIT_NOT, curChar, l.currentLineNumber)
 	case '"':
 		tok.Type = token.STRING
 		tok.Line = l.currentLineNumber
-		tok.Literal = string(l.readString())
+		str, msg := l.readString()
+		if msg != "" {
+			tok = newToken(token.ILLEGAL, msg, tok.Line)
+		} else {
+			tok.Literal = str
+		}
+	case '`':
+		tok.Type = token.STRING
+		tok.Line = l.currentLineNumber
+		str, msg := l.readRawString()
+		if msg != "" {
+			tok = newToken(token.ILLEGAL, msg, tok.Line)
+		} else {
+			tok.Literal = str
+		}
+	case '?':
+		switch l.peekChar() {
+		case '?':
+			l.readChar()
+			tok = newToken(token.NULLISH, "??", l.currentLineNumber)
+		case '.':
+			l.readChar()
+			tok = newToken(token.OPTIONAL_DOT, "?.", l.currentLineNumber)
+		case '[':
+			l.readChar()
+			tok = newToken(token.OPTIONAL_LBRACKET, "?[", l.currentLineNumber)
+		default:
+			tok = newToken(token.ILLEGAL, fmt.Sprintf("illegal character %q", l.ch), l.currentLineNumber)
+		}
 	case ';':
 		tok = newToken(token.SEMICOLON, curChar, l.currentLineNumber)
 	case ':':
@@ -199,6 +463,22 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.RPAREN, curChar, l.currentLineNumber)
 	case ',':
 		tok = newToken(token.COMMA, curChar, l.currentLineNumber)
+	case '.':
+		if l.peekChar() != '.' {
+			tok = newToken(token.DOT, curChar, l.currentLineNumber)
+			break
+		}
+		l.readChar()
+		switch l.peekChar() {
+		case '.':
+			l.readChar()
+			tok = newToken(token.ELLIPSIS, "...", l.currentLineNumber)
+		case '<':
+			l.readChar()
+			tok = newToken(token.RANGE_EXCLUSIVE, "..<", l.currentLineNumber)
+		default:
+			tok = newToken(token.RANGE, "..", l.currentLineNumber)
+		}
 	case '{':
 		tok = newToken(token.LBRACE, curChar, l.currentLineNumber)
 	case '}':
@@ -208,19 +488,63 @@ func (l *Lexer) NextToken() token.Token {
 	case ']':
 		tok = newToken(token.RBRACKET, curChar, l.currentLineNumber)
 	case '+':
-		tok = newToken(token.PLUS, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.PLUS_ASSIGN, curChar+string(l.ch), l.currentLineNumber)
+		} else {
+			tok = newToken(token.PLUS, curChar, l.currentLineNumber)
+		}
 	case '-':
-		tok = newToken(token.MINUS, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.MINUS_ASSIGN, curChar+string(l.ch), l.currentLineNumber)
+		} else {
+			tok = newToken(token.MINUS, curChar, l.currentLineNumber)
+		}
 	case '*':
-		tok = newToken(token.ASTERISK, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.ASTERISK_ASSIGN, curChar+string(l.ch), l.currentLineNumber)
+		} else if l.peekChar() == '*' {
+			l.readChar()
+			tok = newToken(token.POWER, "**", l.currentLineNumber)
+		} else {
+			tok = newToken(token.ASTERISK, curChar, l.currentLineNumber)
+		}
 	case '/':
-		tok = newToken(token.SLASH, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.SLASH_ASSIGN, curChar+string(l.ch), l.currentLineNumber)
+		} else {
+			tok = newToken(token.SLASH, curChar, l.currentLineNumber)
+		}
 	case '%':
-		tok = newToken(token.MODULO, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.MODULO_ASSIGN, curChar+string(l.ch), l.currentLineNumber)
+		} else {
+			tok = newToken(token.MODULO, curChar, l.currentLineNumber)
+		}
 	case '<':
-		tok = newToken(token.LT, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.LT_EQUAL, curChar+string(l.ch), l.currentLineNumber)
+		} else if l.peekChar() == '<' {
+			l.readChar()
+			tok = newToken(token.SHIFT_LEFT, "<<", l.currentLineNumber)
+		} else {
+			tok = newToken(token.LT, curChar, l.currentLineNumber)
+		}
 	case '>':
-		tok = newToken(token.GT, curChar, l.currentLineNumber)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.GT_EQUAL, curChar+string(l.ch), l.currentLineNumber)
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = newToken(token.SHIFT_RIGHT, ">>", l.currentLineNumber)
+		} else {
+			tok = newToken(token.GT, curChar, l.currentLineNumber)
+		}
 
 	case 0:
 		tok = newToken(token.EOF, "", l.currentLineNumber)
@@ -230,22 +554,28 @@ func (l *Lexer) NextToken() token.Token {
 			identifier := string(l.readIdentifier())
 			tokenType := token.LookUpIdent(identifier)
 			token := newToken(tokenType, identifier, l.currentLineNumber)
+			token.Trivia = trivia
 
 			l.addToken(token) // add token to l.tokens
 			return token
 		} else if isDigit(l.ch) {
-			tok.Literal = string(l.readNumber())
-			tok.Type = token.INT
 			tok.Line = l.currentLineNumber
+			number, tokenType, msg := l.readNumber()
+			tok.Literal, tok.Type = number, tokenType
+			if msg != "" {
+				tok.Literal, tok.Type = msg, token.ILLEGAL
+			}
+			tok.Trivia = trivia
 			l.addToken(tok)
 			return tok
 		} else {
-			tok = newToken(token.ILLEGAL, curChar, l.currentLineNumber)
+			tok = newToken(token.ILLEGAL, fmt.Sprintf("illegal character %q", l.ch), l.currentLineNumber)
 		}
 	}
 
 	// read next char
 	l.readChar()
+	tok.Trivia = trivia
 	l.addToken(tok)
 	return tok
 }
@@ -263,14 +593,15 @@ func New(recieve <-chan []byte) *Lexer {
 }
 
 func NewFromString(input string) *Lexer {
-	l := &Lexer{}
+	l := &Lexer{currentLineNumber: 1}
 	l.buffer1 = []rune(input)
+	l.CurBuf = &l.buffer1
 	l.readChar()
 	return l
 }
 
 func isLetter(char rune) bool {
-	return regexp.MustCompile("^[a-zA-z_]$").MatchString(string(char))
+	return regexp.MustCompile("^[a-zA-Z_]$").MatchString(string(char))
 }
 
 func isIdentifierCharacter(char rune) bool {
@@ -283,7 +614,19 @@ func isIdentifierCharacter(char rune) bool {
 // }
 
 func isDigit(char rune) bool {
-	return regexp.MustCompile("^[0-9.]$").MatchString(string(char))
+	return regexp.MustCompile("^[0-9]$").MatchString(string(char))
+}
+
+func isOctalDigit(char rune) bool {
+	return regexp.MustCompile("^[0-7]$").MatchString(string(char))
+}
+
+func isBinaryDigit(char rune) bool {
+	return regexp.MustCompile("^[01]$").MatchString(string(char))
+}
+
+func isHexDigit(char rune) bool {
+	return regexp.MustCompile("^[0-9a-fA-F]$").MatchString(string(char))
 }
 
 func newToken(tokenType token.TokenType, attribute string, lineNumber int) token.Token {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -13,6 +14,8 @@ import (
 )
 
 func main() {
+	flag.BoolVar(&evaluator.CheckedArithmetic, "checked", false, "report integer overflow instead of wrapping")
+	flag.Parse()
 	repl.Start(os.Stdin, os.Stdout)
 	// create channels
 	fileToLexChan := make(chan []byte)
@@ -26,7 +29,17 @@ func main() {
 
 	parser := parser.New(lexer)
 	program := parser.ParseProgram()
+	if len(parser.Errors()) != 0 {
+		for _, msg := range parser.Errors() {
+			fmt.Fprintln(os.Stderr, msg)
+		}
+		os.Exit(1)
+	}
 	fmt.Println(program)
 	env := object.NewEnvirnoment()
-	evaluator.Eval(program, env)
+	env.SetFile(fileReader.FileName)
+	if err, ok := evaluator.Eval(program, env).(*object.ErrorObject); ok {
+		fmt.Fprintln(os.Stderr, err.Inspect())
+		os.Exit(1)
+	}
 }
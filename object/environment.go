@@ -1,8 +1,10 @@
 package object
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store     map[string]Object
+	constants map[string]bool // names in store bound by const
+	outer     *Environment
+	file      string // source file of a top level environment
 }
 
 func (env *Environment) Get(name string) (Object, bool) {
@@ -18,9 +20,47 @@ func (env *Environment) Set(name string, object Object) Object {
 	return object
 }
 
+// Resolve returns the innermost environment in the outer chain that binds name, or nil
+func (env *Environment) Resolve(name string) *Environment {
+	for scope := env; scope != nil; scope = scope.outer {
+		if _, ok := scope.store[name]; ok {
+			return scope
+		}
+	}
+	return nil
+}
+
+// SetConstant binds name like Set and marks the binding read-only
+func (env *Environment) SetConstant(name string, object Object) Object {
+	env.store[name] = object
+	env.constants[name] = true
+	return object
+}
+
+// IsConstant reports whether name is bound by const in this scope, outer scopes are not consulted
+func (env *Environment) IsConstant(name string) bool {
+	return env.constants[name]
+}
+
+// SetFile records the source file evaluated in this environment, imports resolve relative to it
+func (env *Environment) SetFile(path string) {
+	env.file = path
+}
+
+// File returns the source file of the nearest environment in the outer chain that has one
+func (env *Environment) File() string {
+	for scope := env; scope != nil; scope = scope.outer {
+		if scope.file != "" {
+			return scope.file
+		}
+	}
+	return ""
+}
+
 func NewEnvirnoment() *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	c := make(map[string]bool)
+	return &Environment{store: s, constants: c, outer: nil}
 }
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
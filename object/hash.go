@@ -22,16 +22,46 @@ type HashPair struct {
 
 // Hash Type object
 // Implements object and Hashable interface
+// Pairs is written through Set so that Keys keeps the insertion order
 type Hash struct {
 	Pairs map[HashKey]HashPair
+	Keys  []HashKey // keys of Pairs in insertion order
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Set binds key to pair, a new key is appended to Keys while an existing key keeps its position
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	if _, ok := h.Pairs[key]; !ok {
+		h.Keys = append(h.Keys, key)
+	}
+	h.Pairs[key] = pair
+}
+
+// OrderedPairs returns the pairs of the hash in insertion order
+func (h *Hash) OrderedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(h.Keys))
+	for _, key := range h.Keys {
+		pairs = append(pairs, h.Pairs[key])
+	}
+	return pairs
 }
 
 func (h *Hash) Type() ObjecType { return HASH_OBJ }
-func (h *Hash) Inspect() string {
+func (h *Hash) Inspect() string { return h.inspect(map[Object]bool{}) }
+func (h *Hash) inspect(seen map[Object]bool) string {
+	if seen[h] {
+		return "{...}"
+	}
+	seen[h] = true
+	defer delete(seen, h)
+
 	var out bytes.Buffer
 	pairs := []string{}
-	for _, pair := range h.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s:%s", pair.Key.Inspect(), pair.Value.Inspect()))
+	for _, pair := range h.OrderedPairs() {
+		pairs = append(pairs, fmt.Sprintf("%s:%s", pair.Key.Inspect(), inspectNested(pair.Value, seen)))
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairs, ", "))
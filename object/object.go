@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/sachinaralapura/shoebill/ast"
@@ -11,15 +15,23 @@ import (
 
 const (
 	INTEGER_OBJ  = "INTEGER"
+	BIGINT_OBJ   = "BIGINT"
+	FLOAT_OBJ    = "FLOAT"
 	STRING_OBJ   = "STRING"
 	BOOLEAN_OBJ  = "BOOLEAN"
 	NULL_OBJ     = "NULL"
 	RETURN_OBJ   = "RETURN"
+	BREAK_OBJ    = "BREAK"
+	CONTINUE_OBJ = "CONTINUE"
 	ERROR_OBJ    = "ERROR"
 	FUCNTION_OBJ = "FUNCTION"
 	BUILDIN_OBJ  = "BUILDIN"
 	ARRAY_OBJ    = "ARRAY"
+	RANGE_OBJ    = "RANGE"
 	HASH_OBJ     = "HASH"
+	CLASS_OBJ    = "CLASS"
+	INSTANCE_OBJ = "INSTANCE"
+	MODULE_OBJ   = "MODULE"
 )
 
 type ObjecType string
@@ -51,6 +63,48 @@ func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+// BigInt Type Object, holds integers outside the int64 range
+// Implements object and Hashable interface
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Inspect() string { return b.Value.String() }
+func (b *BigInt) Type() ObjecType { return BIGINT_OBJ }
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+// Float Type Object
+// Implements object and Hashable interface
+type Float struct {
+	Value float64
+}
+
+// Inspect keeps a decimal point on integral values so 2.0 is not shown as an integer
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if strings.ContainsAny(s, ".eIN") {
+		return s
+	}
+	return s + ".0"
+}
+func (f *Float) Type() ObjecType { return FLOAT_OBJ }
+
+// HashKey of an integral float is the key of the equal integer, so 2.0 finds the pair of 2
+func (f *Float) HashKey() HashKey {
+	if f.Value == math.Trunc(f.Value) && !math.IsInf(f.Value, 0) {
+		if f.Value >= math.MinInt64 && f.Value < math.MaxInt64 {
+			return (&Integer{Value: int64(f.Value)}).HashKey()
+		}
+		value, _ := big.NewFloat(f.Value).Int(nil)
+		return (&BigInt{Value: value}).HashKey()
+	}
+	return HashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
+
 // String Type Object
 // Implements object and Hashable interface
 type String struct {
@@ -89,11 +143,18 @@ type Array struct {
 }
 
 func (a *Array) Type() ObjecType { return ARRAY_OBJ }
-func (a *Array) Inspect() string {
+func (a *Array) Inspect() string { return a.inspect(map[Object]bool{}) }
+func (a *Array) inspect(seen map[Object]bool) string {
+	if seen[a] {
+		return "[...]"
+	}
+	seen[a] = true
+	defer delete(seen, a)
+
 	var out bytes.Buffer
 	elements := []string{}
 	for _, e := range a.Elements {
-		elements = append(elements, e.Inspect())
+		elements = append(elements, inspectNested(e, seen))
 	}
 	out.WriteString("[")
 	out.WriteString(strings.Join(elements, ","))
@@ -101,6 +162,62 @@ func (a *Array) Inspect() string {
 	return out.String()
 }
 
+// Range Type Object
+// a lazy sequence of integers from Start towards End, End is included unless Exclusive
+type Range struct {
+	Start     int64
+	End       int64
+	Step      int64 // never zero
+	Exclusive bool
+}
+
+func (r *Range) Type() ObjecType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	operator := ".."
+	if r.Exclusive {
+		operator = "..<"
+	}
+	out := fmt.Sprintf("%d%s%d", r.Start, operator, r.End)
+	if r.Step != 1 {
+		out += fmt.Sprintf(" step %d", r.Step)
+	}
+	return out
+}
+
+// Count returns the number of integers in the range, ok is false when that number does not fit in int64
+func (r *Range) Count() (count int64, ok bool) {
+	// the distance between the bounds is computed on uint64 where it cannot overflow
+	var span, stride uint64
+	if r.Step > 0 {
+		if r.End < r.Start || (r.Exclusive && r.End == r.Start) {
+			return 0, true
+		}
+		span, stride = uint64(r.End)-uint64(r.Start), uint64(r.Step)
+	} else {
+		if r.End > r.Start || (r.Exclusive && r.End == r.Start) {
+			return 0, true
+		}
+		span, stride = uint64(r.Start)-uint64(r.End), -uint64(r.Step)
+	}
+	if r.Exclusive {
+		span--
+	}
+	steps := span / stride
+	if steps >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(steps) + 1, true
+}
+
+// Len returns the number of integers in the range, the evaluator only creates ranges for which Count is ok
+func (r *Range) Len() int64 {
+	count, _ := r.Count()
+	return count
+}
+
+// At returns the i-th integer of the range, i must be below Len
+func (r *Range) At(i int64) int64 { return r.Start + i*r.Step }
+
 // Null Type Object
 type Null struct{}
 
@@ -115,9 +232,30 @@ type Return struct {
 func (r *Return) Inspect() string { return r.Value.Inspect() }
 func (r *Return) Type() ObjecType { return RETURN_OBJ }
 
+// Break Object
+// signals the nearest enclosing loop, or the loop named by Label, to stop
+type Break struct {
+	Label string
+}
+
+func (b *Break) Inspect() string { return "break" }
+func (b *Break) Type() ObjecType { return BREAK_OBJ }
+
+// Continue Object
+// signals the nearest enclosing loop, or the loop named by Label, to start its next iteration
+type Continue struct {
+	Label string
+}
+
+func (c *Continue) Inspect() string { return "continue" }
+func (c *Continue) Type() ObjecType { return CONTINUE_OBJ }
+
 // Function Literal Object
 type FunctionObject struct {
+	Name       string // binding or method name used in error messages, empty for anonymous functions
 	Parameters []*ast.Identifier
+	Defaults   []ast.Expression
+	Rest       *ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -125,10 +263,7 @@ type FunctionObject struct {
 func (f *FunctionObject) Type() ObjecType { return FUCNTION_OBJ }
 func (f *FunctionObject) Inspect() string {
 	var out bytes.Buffer
-	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
-	}
+	params := ast.FormatParameters(f.Parameters, f.Defaults, f.Rest)
 	out.WriteString("fn")
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
@@ -138,10 +273,97 @@ func (f *FunctionObject) Inspect() string {
 	return out.String()
 }
 
+// Class Object
+// calling a class creates an Instance and runs its init method
+type Class struct {
+	Name    string
+	Fields  []*ast.LetStatement // field initializers, evaluated per instance
+	Methods map[string]*FunctionObject
+	Env     *Environment
+}
+
+func (c *Class) Type() ObjecType { return CLASS_OBJ }
+func (c *Class) Inspect() string { return "class " + c.Name }
+
+// Instance Object
+type Instance struct {
+	Class  *Class
+	Fields map[string]Object
+}
+
+func (i *Instance) Type() ObjecType { return INSTANCE_OBJ }
+func (i *Instance) Inspect() string { return i.inspect(map[Object]bool{}) }
+func (i *Instance) inspect(seen map[Object]bool) string {
+	if seen[i] {
+		return i.Class.Name + "{...}"
+	}
+	seen[i] = true
+	defer delete(seen, i)
+
+	var out bytes.Buffer
+	names := make([]string, 0, len(i.Fields))
+	for name := range i.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := []string{}
+	for _, name := range names {
+		fields = append(fields, fmt.Sprintf("%s:%s", name, inspectNested(i.Fields[name], seen)))
+	}
+	out.WriteString(i.Class.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// inspectNested inspects obj as part of an enclosing value, seen holds the values
+// being inspected further out which print as an ellipsis instead of recursing forever
+func inspectNested(obj Object, seen map[Object]bool) string {
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.inspect(seen)
+	case *Hash:
+		return obj.inspect(seen)
+	case *Instance:
+		return obj.inspect(seen)
+	default:
+		return obj.Inspect()
+	}
+}
+
+// Module Object
+// namespace holding the exported bindings of an imported file,
+// exports are read from Env so they see later assignments inside the module
+type Module struct {
+	Path    string
+	Env     *Environment
+	Exports map[string]bool // exported names bound in Env
+}
+
+func (m *Module) Type() ObjecType { return MODULE_OBJ }
+func (m *Module) Inspect() string { return "module " + m.Path }
+
+// kinds of ErrorObject
+const (
+	RUNTIME_ERROR = "RuntimeError"
+	TYPE_ERROR    = "TypeError"
+	THROWN_ERROR  = "Error" // default kind of values raised by throw
+)
+
 // Error Object
+// runtime errors and thrown values, both can be caught by try catch
 type ErrorObject struct {
 	Message string
+	Kind    string
+	Line    int    // line of the statement that raised the error, 0 if unknown
+	Value   Object // value passed to throw, nil for runtime errors
 }
 
-func (e *ErrorObject) Inspect() string { return e.Message }
+func (e *ErrorObject) Inspect() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
 func (e *ErrorObject) Type() ObjecType { return ERROR_OBJ }
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/sachinaralapura/shoebill/ast"
@@ -12,13 +14,21 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // =
+	NULLISH     // ??
 	OR          // ||
 	AND         // &&
 	EQUALS      // ==
-	LESSGREATER // > or <
+	LESSGREATER // > or < or >= or <=
+	RANGE       // .. or ..<
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
+	SHIFT       // << or >>
 	SUM         // +
 	PRODUCT     // *
-	PREFIX      // -X or !X
+	PREFIX      // -X or !X or ~X
+	POWER       // **
 	CALL        // myFunction(X)
 	INDEX
 )
@@ -31,6 +41,8 @@ var precedences = map[token.TokenType]int{
 	token.NOT_EQUAL: EQUALS,
 	token.LT:        LESSGREATER,
 	token.GT:        LESSGREATER,
+	token.LT_EQUAL:  LESSGREATER,
+	token.GT_EQUAL:  LESSGREATER,
 	token.PLUS:      SUM,
 	token.MINUS:     SUM,
 	token.SLASH:     PRODUCT,
@@ -38,6 +50,28 @@ var precedences = map[token.TokenType]int{
 	token.MODULO:    PRODUCT,
 	token.LPAREN:    CALL,
 	token.LBRACKET:  INDEX,
+	token.DOT:       INDEX,
+	token.NULLISH:   NULLISH,
+
+	token.RANGE:           RANGE,
+	token.RANGE_EXCLUSIVE: RANGE,
+
+	token.BIT_OR:      BITOR,
+	token.BIT_XOR:     BITXOR,
+	token.BIT_AND:     BITAND,
+	token.SHIFT_LEFT:  SHIFT,
+	token.SHIFT_RIGHT: SHIFT,
+	token.POWER:       POWER,
+
+	token.OPTIONAL_DOT:      INDEX,
+	token.OPTIONAL_LBRACKET: INDEX,
+	token.ASSIGN:            ASSIGN,
+
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.MODULO_ASSIGN:   ASSIGN,
 }
 
 type (
@@ -52,6 +86,9 @@ type Parser struct {
 	curToken  token.Token
 	peekToken token.Token
 
+	// labels of the loops enclosing the current statement, "" for unlabeled loops
+	loops []string
+
 	// sendChan chan<- []byte
 
 	prefixParseFns map[token.TokenType]prefixParseFn
@@ -106,8 +143,31 @@ func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.LET:
 		return p.parseLetStatement()
+	case token.CONST:
+		return p.parseConstStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.WHILE:
+		return p.parseWhileStatement(nil)
+	case token.FOR:
+		return p.parseForStatement(nil)
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.IDENT:
+		if p.peekTokenIs(token.COLON) {
+			return p.parseLabeledStatement()
+		}
+		return p.parseExpressionStatement()
+	case token.CLASS:
+		return p.parseClassStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -116,7 +176,120 @@ func (p *Parser) parseStatement() ast.Statement {
 // parse Let Statements
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken}
-	// check if next token is Identifier
+	switch {
+	case p.peekTokenIs(token.LBRACKET):
+		p.NextToken()
+		if stmt.Pattern = p.parseArrayPattern(); stmt.Pattern == nil {
+			return nil
+		}
+	case p.peekTokenIs(token.LBRACE):
+		p.NextToken()
+		if stmt.Pattern = p.parseHashPattern(); stmt.Pattern == nil {
+			return nil
+		}
+	default:
+		// check if next token is Identifier
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	p.NextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
+// parse array destructuring pattern
+//
+//	[<identifier>, <identifier> = <expression>, ...<identifier>]
+func (p *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACKET) {
+		if p.peekTokenIs(token.ELLIPSIS) {
+			p.NextToken()
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.peekTokenIs(token.RBRACKET) {
+				msg := fmt.Sprintf("rest element ...%s must be the last element", pattern.Rest.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			break
+		}
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		element := &ast.PatternElement{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+		element.Default = p.parsePatternDefault()
+		pattern.Elements = append(pattern.Elements, element)
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return pattern
+}
+
+// parse hash destructuring pattern, string keys need a binding name
+//
+//	{<identifier>, <identifier>: <identifier> = <expression>, <string>: <identifier>}
+func (p *Parser) parseHashPattern() ast.Expression {
+	pattern := &ast.HashPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.NextToken()
+		element := &ast.PatternElement{}
+		switch p.curToken.Type {
+		case token.IDENT:
+			element.Key = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		case token.STRING:
+			element.Key = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+		default:
+			msg := fmt.Sprintf("expected identifier or string in hash pattern, got %s", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		if p.peekTokenIs(token.COLON) || p.curTokenIs(token.STRING) {
+			if !p.expectPeek(token.COLON) || !p.expectPeek(token.IDENT) {
+				return nil
+			}
+		}
+		element.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		element.Default = p.parsePatternDefault()
+		pattern.Elements = append(pattern.Elements, element)
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return pattern
+}
+
+// parsePatternDefault parses the optional '= <expression>' after a pattern element
+func (p *Parser) parsePatternDefault() ast.Expression {
+	if !p.peekTokenIs(token.ASSIGN) {
+		return nil
+	}
+	p.NextToken()
+	p.NextToken()
+	return p.parseExpression(LOWEST)
+}
+
+// parse Const Statements
+func (p *Parser) parseConstStatement() ast.Statement {
+	stmt := &ast.ConstStatement{Token: p.curToken}
 	if !p.expectPeek(token.IDENT) {
 		return nil
 	}
@@ -144,6 +317,302 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return returnStmt
 }
 
+// parse Labeled Statement, only loops can be labeled
+//
+//	<identifier>: while (<condition>) { <statements> }
+func (p *Parser) parseLabeledStatement() ast.Statement {
+	label := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	p.NextToken()
+	p.NextToken()
+	switch p.curToken.Type {
+	case token.WHILE:
+		return p.parseWhileStatement(label)
+	case token.FOR:
+		return p.parseForStatement(label)
+	default:
+		msg := fmt.Sprintf("label %s must be followed by a loop, got %s", label.Value, p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+}
+
+// parseLoopBody parses the block of a loop, break and continue are allowed inside it
+func (p *Parser) parseLoopBody(label *ast.Identifier) *ast.BlockStatement {
+	name := ""
+	if label != nil {
+		name = label.Value
+	}
+	p.loops = append(p.loops, name)
+	body := p.parseBlockExpression()
+	p.loops = p.loops[:len(p.loops)-1]
+	return body
+}
+
+// parse the optional label of break and continue, it must name an enclosing loop
+func (p *Parser) parseLoopControlLabel(keyword string) (*ast.Identifier, bool) {
+	if len(p.loops) == 0 {
+		msg := fmt.Sprintf("%s outside of loop", keyword)
+		p.errors = append(p.errors, msg)
+		return nil, false
+	}
+	if !p.peekTokenIs(token.IDENT) {
+		return nil, true
+	}
+	p.NextToken()
+	label := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	for _, name := range p.loops {
+		if name == label.Value {
+			return label, true
+		}
+	}
+	msg := fmt.Sprintf("%s to undefined label %s", keyword, label.Value)
+	p.errors = append(p.errors, msg)
+	return nil, false
+}
+
+// parse Break Statement
+func (p *Parser) parseBreakStatement() ast.Statement {
+	stmt := &ast.BreakStatement{Token: p.curToken}
+	label, ok := p.parseLoopControlLabel("break")
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	if !ok {
+		return nil
+	}
+	stmt.Label = label
+	return stmt
+}
+
+// parse Continue Statement
+func (p *Parser) parseContinueStatement() ast.Statement {
+	stmt := &ast.ContinueStatement{Token: p.curToken}
+	label, ok := p.parseLoopControlLabel("continue")
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	if !ok {
+		return nil
+	}
+	stmt.Label = label
+	return stmt
+}
+
+// parse While Statement
+//
+//	while (<condition>) { <statements> }
+func (p *Parser) parseWhileStatement(label *ast.Identifier) ast.Statement {
+	stmt := &ast.WhileStatement{Token: p.curToken, Label: label}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.NextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseLoopBody(stmt.Label)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
+// parse For Statement, both the C-style and the for-in form
+//
+//	for (<init>; <condition>; <update>) { <statements> }
+//	for (<identifier> in <expression>) { <statements> }
+func (p *Parser) parseForStatement(label *ast.Identifier) ast.Statement {
+	forToken := p.curToken
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.NextToken()
+	if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.IN) {
+		return p.parseForInStatement(forToken, label)
+	}
+
+	stmt := &ast.ForStatement{Token: forToken, Label: label}
+	// init ; the statement parsers leave curToken on the trailing ';'
+	if !p.curTokenIs(token.SEMICOLON) {
+		stmt.Init = p.parseStatement()
+		if !p.curTokenIs(token.SEMICOLON) && !p.expectPeek(token.SEMICOLON) {
+			return nil
+		}
+	}
+	// condition
+	p.NextToken()
+	if !p.curTokenIs(token.SEMICOLON) {
+		stmt.Condition = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.SEMICOLON) {
+			return nil
+		}
+	}
+	// update
+	p.NextToken()
+	if !p.curTokenIs(token.RPAREN) {
+		stmt.Update = p.parseStatement()
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseLoopBody(stmt.Label)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
+// parse the remainder of a for-in statement, curToken is the loop variable
+func (p *Parser) parseForInStatement(forToken token.Token, label *ast.Identifier) ast.Statement {
+	stmt := &ast.ForInStatement{Token: forToken, Label: label}
+	stmt.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	p.NextToken() // 'in'
+	p.NextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseLoopBody(stmt.Label)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
+// parse Class Statement, the body holds let fields and fn methods
+func (p *Parser) parseClassStatement() ast.Statement {
+	stmt := &ast.ClassStatement{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.NextToken()
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		switch p.curToken.Type {
+		case token.LET:
+			field := p.parseLetStatement()
+			if field == nil {
+				return nil
+			}
+			if field.Pattern != nil {
+				msg := fmt.Sprintf("field of class %s cannot be a destructuring pattern", stmt.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			stmt.Fields = append(stmt.Fields, field)
+		case token.FUNCTION:
+			method := p.parseMethod()
+			if method == nil {
+				return nil
+			}
+			stmt.Methods = append(stmt.Methods, method)
+		default:
+			msg := fmt.Sprintf("unexpected %s in body of class %s", p.curToken.Type, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		p.NextToken()
+	}
+	if !p.curTokenIs(token.RBRACE) {
+		p.peekErrors(token.RBRACE)
+		return nil
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
+// parse class method
+//
+//	fn <identifier>(<params>) { <statements> }
+func (p *Parser) parseMethod() *ast.FunctionLiteral {
+	method := &ast.FunctionLiteral{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	method.Name = p.curToken.Literal
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	if !p.parseFunctionParameters(method) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	method.Body = p.parseFunctionBody()
+	return method
+}
+
+// parse Import Statement
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+	stmt.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.AS) {
+		return nil
+	}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
+// parse Export Statement
+func (p *Parser) parseExportStatement() ast.Statement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+	p.NextToken()
+	switch p.curToken.Type {
+	case token.LET:
+		if let := p.parseLetStatement(); let != nil {
+			stmt.Statement = let
+		}
+	case token.CONST, token.CLASS:
+		stmt.Statement = p.parseStatement()
+	default:
+		msg := fmt.Sprintf("export must be followed by let, const or class, got %s", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+	}
+	if stmt.Statement == nil {
+		return nil
+	}
+	return stmt
+}
+
+// parse Throw Statement
+func (p *Parser) parseThrowStatement() ast.Statement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+	p.NextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		return nil
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+	return stmt
+}
+
 // parse expression Statement
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
@@ -182,11 +651,11 @@ func (p *Parser) parseExpressionList(endToken token.TokenType) []ast.Expression
 		return expressionList
 	}
 	p.NextToken()
-	expressionList = append(expressionList, p.parseExpression(LOWEST))
+	expressionList = append(expressionList, p.parseListElement())
 	for p.peekTokenIs(token.COMMA) {
 		p.NextToken()
 		p.NextToken()
-		expressionList = append(expressionList, p.parseExpression(LOWEST))
+		expressionList = append(expressionList, p.parseListElement())
 	}
 	if !p.expectPeek(endToken) {
 		return nil
@@ -194,11 +663,31 @@ func (p *Parser) parseExpressionList(endToken token.TokenType) []ast.Expression
 	return expressionList
 }
 
+// parse an element of an array literal or call, which may be spread
+//
+//	<expression> or ...<expression>
+func (p *Parser) parseListElement() ast.Expression {
+	if !p.curTokenIs(token.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+	spread := &ast.SpreadExpression{Token: p.curToken}
+	p.NextToken()
+	spread.Value = p.parseExpression(LOWEST)
+	return spread
+}
+
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s found", t)
 	p.errors = append(p.errors, msg)
 }
 
+// parse Illegal token, the lexer stores its diagnostic in the literal
+func (p *Parser) parseIllegal() ast.Expression {
+	msg := fmt.Sprintf("line %d: %s", p.curToken.Line, p.curToken.Literal)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
 // parse Identifier Expressions
 func (p *Parser) parseIdentifier() ast.Expression {
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
@@ -208,6 +697,11 @@ func (p *Parser) parseIdentifier() ast.Expression {
 func (p *Parser) parseIntegerExpression() ast.Expression {
 	integerLiteral := &ast.IntegerLiteral{Token: p.curToken}
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		if value, ok := new(big.Int).SetString(p.curToken.Literal, 0); ok {
+			return &ast.BigIntLiteral{Token: p.curToken, Value: value}
+		}
+	}
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
 		p.errors = append(p.errors, msg)
@@ -217,6 +711,19 @@ func (p *Parser) parseIntegerExpression() ast.Expression {
 	return integerLiteral
 }
 
+// parse Float Literal
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	floatLiteral := &ast.FloatLiteral{Token: p.curToken}
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	floatLiteral.Value = value
+	return floatLiteral
+}
+
 // parse String Literal
 func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
@@ -236,9 +743,14 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 
 // parse Array Index Expression
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
-	p.NextToken()
-	exp.Index = p.parseExpression(LOWEST)
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left, Optional: p.curTokenIs(token.OPTIONAL_LBRACKET)}
+	if !p.peekTokenIs(token.COLON) {
+		p.NextToken()
+		exp.Index = p.parseExpression(LOWEST)
+	}
+	if p.peekTokenIs(token.COLON) {
+		return p.parseSliceExpression(exp)
+	}
 
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
@@ -246,12 +758,96 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+// parse the rest of a slice after its start, index holds the left side and the start
+//
+//	<expression>[<start>:<end>:<step>]
+func (p *Parser) parseSliceExpression(index *ast.IndexExpression) ast.Expression {
+	exp := &ast.SliceExpression{Token: index.Token, Left: index.Left, Start: index.Index, Optional: index.Optional}
+	p.NextToken()
+	if !p.peekTokenIs(token.COLON) && !p.peekTokenIs(token.RBRACKET) {
+		p.NextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
+	if p.peekTokenIs(token.COLON) {
+		p.NextToken()
+		if !p.peekTokenIs(token.RBRACKET) {
+			p.NextToken()
+			exp.Step = p.parseExpression(LOWEST)
+		}
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return exp
+}
+
+// parse Range Expression, step is only a keyword right after the end of a range
+//
+//	<expression>..<expression> step <expression>
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{Token: p.curToken, Start: start, Exclusive: p.curTokenIs(token.RANGE_EXCLUSIVE)}
+	p.NextToken()
+	exp.End = p.parseExpression(RANGE)
+	if p.peekTokenIs(token.IDENT) && p.peekToken.Literal == "step" {
+		p.NextToken()
+		p.NextToken()
+		exp.Step = p.parseExpression(RANGE)
+	}
+	return exp
+}
+
+// parse Dot Expression
+func (p *Parser) parseDotExpression(left ast.Expression) ast.Expression {
+	exp := &ast.DotExpression{Token: p.curToken, Left: left, Optional: p.curTokenIs(token.OPTIONAL_DOT)}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return exp
+}
+
+// parse Assign Expression, assignment is right associative
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	exp := &ast.AssignExpression{Token: p.curToken, Target: target, Operator: p.curToken.Literal}
+	switch target := target.(type) {
+	case nil:
+		// the target failed to parse and is already reported
+		return nil
+	case *ast.Identifier:
+	case *ast.DotExpression, *ast.IndexExpression:
+		if ast.IsOptionalChain(target) {
+			msg := fmt.Sprintf("invalid assignment target %s, optional chains cannot be assigned", target)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+	default:
+		msg := fmt.Sprintf("invalid assignment target %s", target)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	p.NextToken()
+	exp.Value = p.parseExpression(ASSIGN - 1)
+	return exp
+}
+
+// parse Null Literal
+func (p *Parser) parseNull() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 // parse Hash Expression
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken}
 	hash.Pairs = make(map[ast.Expression]ast.Expression)
 	for !p.peekTokenIs(token.RBRACE) {
 		p.NextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			hash.Order = append(hash.Order, p.parseListElement())
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
 		key := p.parseExpression(LOWEST)
 		if !p.expectPeek(token.COLON) {
 			return nil
@@ -259,6 +855,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		p.NextToken()
 		value := p.parseExpression(LOWEST)
 		hash.Pairs[key] = value
+		hash.Order = append(hash.Order, key)
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
 		}
@@ -296,6 +893,19 @@ func (p *Parser) parseIfExpression() ast.Expression {
 
 	if p.peekTokenIs(token.ELSE) {
 		p.NextToken()
+		if p.peekTokenIs(token.IF) {
+			// else if : the nested if expression becomes the whole alternative block
+			p.NextToken()
+			ifToken := p.curToken
+			nested := p.parseIfExpression()
+			if nested == nil {
+				return nil
+			}
+			expression.Alternative = &ast.BlockStatement{Token: ifToken, Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: ifToken, Expression: nested},
+			}}
+			return expression
+		}
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -304,6 +914,96 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+// parse Try Expression, at least one of catch and finally has to follow the try block
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	expression.Block = p.parseBlockExpression()
+	if p.peekTokenIs(token.CATCH) {
+		p.NextToken()
+		if p.peekTokenIs(token.LPAREN) {
+			p.NextToken()
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			expression.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		expression.Catch = p.parseBlockExpression()
+	}
+	if p.peekTokenIs(token.FINALLY) {
+		p.NextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		expression.Finally = p.parseBlockExpression()
+	}
+	if expression.Catch == nil && expression.Finally == nil {
+		msg := fmt.Sprintf("expected catch or finally after try block, got %s instead", p.peekToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	return expression
+}
+
+// parse Match Expression, arms are separated by commas and their body
+// is either a block or a single expression
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.NextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.NextToken()
+		arm := &ast.MatchArm{}
+		arm.Patterns = append(arm.Patterns, p.parseExpression(LOWEST))
+		for p.peekTokenIs(token.COMMA) {
+			p.NextToken()
+			p.NextToken()
+			arm.Patterns = append(arm.Patterns, p.parseExpression(LOWEST))
+		}
+		if !p.expectPeek(token.FAT_ARROW) {
+			return nil
+		}
+		p.NextToken()
+		if p.curTokenIs(token.LBRACE) {
+			arm.Body = p.parseBlockExpression()
+			if p.peekTokenIs(token.COMMA) {
+				p.NextToken()
+			}
+		} else {
+			armToken := p.curToken
+			body := p.parseExpression(LOWEST)
+			arm.Body = &ast.BlockStatement{Token: armToken, Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: armToken, Expression: body},
+			}}
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		expression.Arms = append(expression.Arms, arm)
+	}
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return expression
+}
+
 func (p *Parser) parseBlockExpression() *ast.BlockStatement {
 	blockedExp := &ast.BlockStatement{Token: p.curToken}
 	blockedExp.Statements = []ast.Statement{}
@@ -324,42 +1024,136 @@ func (p *Parser) parseFunctionExpression() ast.Expression {
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
-	fnExpression.Parameters = p.parseFunctionParameters()
+	if !p.parseFunctionParameters(fnExpression) {
+		return nil
+	}
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
-	fnExpression.Body = p.parseBlockExpression()
+	fnExpression.Body = p.parseFunctionBody()
 	return fnExpression
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// parseFunctionBody parses the block of a function, loops around the function
+// do not extend into it so break and continue cannot cross a function boundary
+func (p *Parser) parseFunctionBody() *ast.BlockStatement {
+	loops := p.loops
+	p.loops = nil
+	body := p.parseBlockExpression()
+	p.loops = loops
+	return body
+}
+
+// parse the parameters of fn up to and including the closing paren
+//
+//	(a, b = <expression>, ...rest)
+func (p *Parser) parseFunctionParameters(fn *ast.FunctionLiteral) bool {
+	fn.Parameters = []*ast.Identifier{}
 	if p.peekTokenIs(token.RPAREN) {
 		p.NextToken()
-		return identifiers
+		return true
 	}
-	p.NextToken()
-	identifier := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, identifier)
-	for p.peekTokenIs(token.COMMA) {
-		p.NextToken()
-		p.NextToken()
+	seen := make(map[string]bool)
+	hasDefaults := false
+	for {
+		rest := p.peekTokenIs(token.ELLIPSIS)
+		if rest {
+			p.NextToken()
+		}
+		if !p.expectPeek(token.IDENT) {
+			return false
+		}
 		identifier := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, identifier)
+		if seen[identifier.Value] {
+			msg := fmt.Sprintf("duplicate parameter %s", identifier.Value)
+			p.errors = append(p.errors, msg)
+			return false
+		}
+		seen[identifier.Value] = true
+
+		if rest {
+			fn.Rest = identifier
+			if !p.peekTokenIs(token.RPAREN) {
+				msg := fmt.Sprintf("rest parameter ...%s must be the last parameter", identifier.Value)
+				p.errors = append(p.errors, msg)
+				return false
+			}
+		} else {
+			var defaultValue ast.Expression
+			if p.peekTokenIs(token.ASSIGN) {
+				p.NextToken()
+				p.NextToken()
+				defaultValue = p.parseExpression(LOWEST)
+				hasDefaults = true
+			} else if hasDefaults {
+				msg := fmt.Sprintf("parameter %s without default follows a parameter with default", identifier.Value)
+				p.errors = append(p.errors, msg)
+				return false
+			}
+			fn.Parameters = append(fn.Parameters, identifier)
+			fn.Defaults = append(fn.Defaults, defaultValue)
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.NextToken()
 	}
-	if !p.expectPeek(token.RPAREN) {
-		return nil
+	if !hasDefaults {
+		fn.Defaults = nil
 	}
-	return identifiers
+	return p.expectPeek(token.RPAREN)
 }
 
 // parse Call Expressions
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	callExp := &ast.CallExpression{Token: p.curToken, Function: function}
-	callExp.Arguments = p.parseExpressionList(token.RPAREN)
+	callExp.Arguments = p.parseCallArguments()
 	return callExp
 }
 
+// parse the arguments of a call up to and including the closing paren,
+// keyword arguments follow the positional ones
+//
+//	(<expression>, <identifier>: <expression>)
+func (p *Parser) parseCallArguments() []ast.Expression {
+	args := []ast.Expression{}
+	if p.peekTokenIs(token.RPAREN) {
+		p.NextToken()
+		return args
+	}
+	keywords := make(map[string]bool)
+	for {
+		p.NextToken()
+		if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) {
+			arg := &ast.KeywordArgument{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+			if keywords[arg.Name.Value] {
+				msg := fmt.Sprintf("keyword argument %s repeated", arg.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			keywords[arg.Name.Value] = true
+			p.NextToken()
+			p.NextToken()
+			arg.Value = p.parseExpression(LOWEST)
+			args = append(args, arg)
+		} else if len(keywords) > 0 {
+			p.errors = append(p.errors, "positional argument follows keyword argument")
+			return nil
+		} else {
+			args = append(args, p.parseListElement())
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.NextToken()
+	}
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return args
+}
+
 // parse Prefix Expression
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	prefixExp := &ast.PrefixExpression{Token: p.curToken, Operator: p.curToken.Literal}
@@ -373,6 +1167,10 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	infixExp := &ast.InfixExpression{Token: p.curToken, Operator: p.curToken.Literal, Left: left}
 	precedence := p.curPrecedence()
+	if p.curTokenIs(token.POWER) {
+		// ** is right associative, 2 ** 3 ** 2 is 2 ** (3 ** 2)
+		precedence--
+	}
 	p.NextToken()
 	infixExp.Right = p.parseExpression(precedence)
 	return infixExp
@@ -415,15 +1213,21 @@ func New(l *lexer.Lexer) *Parser {
 	p := &Parser{l: l, errors: []string{}}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerExpression)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.BIT_NOT, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNull)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionExpression)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
@@ -438,10 +1242,30 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.NOT_EQUAL, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQUAL, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQUAL, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.OR, p.parseInfixExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseDotExpression)
+	p.registerInfix(token.OPTIONAL_DOT, p.parseDotExpression)
+	p.registerInfix(token.OPTIONAL_LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.NULLISH, p.parseInfixExpression)
+	p.registerInfix(token.BIT_AND, p.parseInfixExpression)
+	p.registerInfix(token.BIT_OR, p.parseInfixExpression)
+	p.registerInfix(token.BIT_XOR, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_LEFT, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseInfixExpression)
+	p.registerInfix(token.POWER, p.parseInfixExpression)
+	p.registerInfix(token.RANGE, p.parseRangeExpression)
+	p.registerInfix(token.RANGE_EXCLUSIVE, p.parseRangeExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MODULO_ASSIGN, p.parseAssignExpression)
 
 	// read next two token so the curToken and peekToken are read
 	p.NextToken()
@@ -34,6 +34,7 @@ func Start(in io.Reader, out io.Writer) {
 		// fmt.Println(program)
 		if len(p.Errors()) != 0 {
 			printParserErrors(out, p.Errors())
+			continue
 		}
 
 		evaluated := evaluator.Eval(program, env)
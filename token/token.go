@@ -17,6 +17,7 @@ const (
 	//Identified + literals
 	IDENT  = "IDENT"
 	INT    = "INT"
+	FLOAT  = "FLOAT"
 	STRING = "STRING"
 
 	//Operators
@@ -29,15 +30,45 @@ const (
 	BANG      = "!"
 	LT        = "<"
 	GT        = ">"
+	LT_EQUAL  = "<="
+	GT_EQUAL  = ">="
 	AND       = "&&"
 	OR        = "||"
 	EQUAL     = "=="
 	NOT_EQUAL = "!="
+	NULLISH   = "??"
+
+	// Bitwise and exponentiation operators
+	BIT_AND     = "&"
+	BIT_OR      = "|"
+	BIT_XOR     = "^"
+	BIT_NOT     = "~"
+	SHIFT_LEFT  = "<<"
+	SHIFT_RIGHT = ">>"
+	POWER       = "**"
+
+	// Range operators
+	RANGE           = ".."
+	RANGE_EXCLUSIVE = "..<"
+
+	// Optional chaining
+	OPTIONAL_DOT      = "?."
+	OPTIONAL_LBRACKET = "?["
+
+	// Assignment operators
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+	MODULO_ASSIGN   = "%="
 
 	// Delimiters
+	DOT       = "."
+	ELLIPSIS  = "..."
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	FAT_ARROW = "=>"
 	LPAREN    = "("
 	RPAREN    = ")"
 	LBRACE    = "{"
@@ -52,24 +83,50 @@ const (
 	CONST    = "CONST"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	NULL     = "NULL"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	FOR      = "FOR"
 	WHILE    = "WHILE"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
 	RETURN   = "RETURN"
 	CLASS    = "CLASS"
+	MATCH    = "MATCH"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	AS       = "AS"
 )
 
 var Keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"const":  CONST,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"class":  CLASS,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"const":    CONST,
+	"true":     TRUE,
+	"false":    FALSE,
+	"null":     NULL,
+	"if":       IF,
+	"else":     ELSE,
+	"for":      FOR,
+	"in":       IN,
+	"while":    WHILE,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"return":   RETURN,
+	"class":    CLASS,
+	"match":    MATCH,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
+	"import":   IMPORT,
+	"export":   EXPORT,
+	"as":       AS,
 }
 
 func LookUpIdent(ident string) TokenType {
@@ -85,4 +142,5 @@ type Token struct {
 	Type    TokenType // token name
 	Literal string    // token attribute
 	Line    int       // line Number
+	Trivia  []string  // comments preceding the token, delimiters included
 }